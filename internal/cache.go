@@ -0,0 +1,329 @@
+package internal
+
+import "sync"
+
+// ChangeDetector is implemented by storages that can cheaply tell
+// whether their data has changed, including by another process.
+// The token is opaque and only compared for equality.
+type ChangeDetector interface {
+	ChangeToken() (string, error)
+}
+
+// changeToken returns the change token of v, or "" if v cannot detect changes.
+func changeToken(v any) string {
+	if d, ok := v.(ChangeDetector); ok {
+		if token, err := d.ChangeToken(); err == nil {
+			return token
+		}
+	}
+	return ""
+}
+
+// changedSince reports whether v may have changed since token was taken.
+// Storages that cannot detect changes are only changed through the cache.
+func changedSince(v any, token string) bool {
+	if _, ok := v.(ChangeDetector); !ok {
+		return false
+	}
+	t := changeToken(v)
+	return t == "" || t != token
+}
+
+// CachedStorage is an in-memory index of a storage.
+// Listings are served from memory; mutations are written through
+// to the underlying storage and applied to the index.
+// Call Sync to pick up changes made outside of this process.
+type CachedStorage struct {
+	Storage
+
+	mu              sync.Mutex
+	version         uint64
+	collections     []string
+	collectionToken string
+	loaded          bool
+	requests        map[string]*CachedRequestStore
+}
+
+func NewCachedStorage(storage Storage) *CachedStorage {
+	return &CachedStorage{
+		Storage:  storage,
+		version:  1,
+		requests: make(map[string]*CachedRequestStore),
+	}
+}
+
+// Version is incremented whenever the cached data changes.
+func (c *CachedStorage) Version() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+func (c *CachedStorage) bump() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+}
+
+func (c *CachedStorage) loadCollections() error {
+	collections, err := c.Storage.ListCollections()
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.collections = collections
+	c.collectionToken = changeToken(c.Storage)
+	c.loaded = true
+	c.version++
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *CachedStorage) invalidateCollections() error {
+	c.mu.Lock()
+	c.loaded = false
+	c.mu.Unlock()
+	return c.loadCollections()
+}
+
+func (c *CachedStorage) ListCollections() ([]string, error) {
+	c.mu.Lock()
+	loaded := c.loaded
+	c.mu.Unlock()
+	if !loaded {
+		if err := c.loadCollections(); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.collections...), nil
+}
+
+func (c *CachedStorage) GetFirstCollection() (string, error) {
+	collections, err := c.ListCollections()
+	if err != nil {
+		return "", err
+	}
+	if len(collections) == 0 {
+		return c.Storage.GetFirstCollection()
+	}
+	return collections[0], nil
+}
+
+func (c *CachedStorage) CollectionExists(collection string) bool {
+	collections, err := c.ListCollections()
+	if err != nil {
+		return c.Storage.CollectionExists(collection)
+	}
+	for _, v := range collections {
+		if v == collection {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CachedStorage) CreateCollection(collection string) error {
+	if err := c.Storage.CreateCollection(collection); err != nil {
+		return err
+	}
+	return c.invalidateCollections()
+}
+
+func (c *CachedStorage) DeleteCollection(collection string) error {
+	if err := c.Storage.DeleteCollection(collection); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	delete(c.requests, collection)
+	c.mu.Unlock()
+	return c.invalidateCollections()
+}
+
+func (c *CachedStorage) RenameCollection(oldName, newName string) error {
+	if err := c.Storage.RenameCollection(oldName, newName); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	delete(c.requests, oldName)
+	delete(c.requests, newName)
+	c.mu.Unlock()
+	return c.invalidateCollections()
+}
+
+func (c *CachedStorage) DuplicateCollection(src, dst string) error {
+	if err := c.Storage.DuplicateCollection(src, dst); err != nil {
+		return err
+	}
+	return c.invalidateCollections()
+}
+
+func (c *CachedStorage) WriteCollectionCatalog(catalog []string) error {
+	if err := c.Storage.WriteCollectionCatalog(catalog); err != nil {
+		return err
+	}
+	return c.invalidateCollections()
+}
+
+func (c *CachedStorage) Requests(collection string) (RequestStorage, error) {
+	c.mu.Lock()
+	store, ok := c.requests[collection]
+	c.mu.Unlock()
+	if ok {
+		return store, nil
+	}
+	inner, err := c.Storage.Requests(collection)
+	if err != nil {
+		return nil, err
+	}
+	store = &CachedRequestStore{RequestStorage: inner, onChange: c.bump}
+	c.mu.Lock()
+	c.requests[collection] = store
+	c.mu.Unlock()
+	return store, nil
+}
+
+// Sync reloads the collection list and the requests of the current
+// collection if they were changed outside of this cache.
+func (c *CachedStorage) Sync() error {
+	c.mu.Lock()
+	token := c.collectionToken
+	c.mu.Unlock()
+	if changedSince(c.Storage, token) {
+		if err := c.loadCollections(); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	store, ok := c.requests[c.Storage.CurrentCollection()]
+	c.mu.Unlock()
+	if ok {
+		return store.Sync()
+	}
+	return nil
+}
+
+// CachedRequestStore is an in-memory index of the requests of a collection.
+type CachedRequestStore struct {
+	RequestStorage
+
+	mu       sync.Mutex
+	requests []Request
+	token    string
+	loaded   bool
+	onChange func()
+}
+
+func (r *CachedRequestStore) load() error {
+	requests, err := r.RequestStorage.ListRequests()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.requests = requests
+	r.token = changeToken(r.RequestStorage)
+	r.loaded = true
+	r.mu.Unlock()
+	r.onChange()
+	return nil
+}
+
+// update applies f to the index after a successful write-through.
+func (r *CachedRequestStore) update(f func([]Request) []Request) {
+	r.mu.Lock()
+	if r.loaded {
+		// copy on write, so that listings returned earlier stay intact
+		r.requests = f(append([]Request(nil), r.requests...))
+		r.token = changeToken(r.RequestStorage)
+	}
+	r.mu.Unlock()
+	r.onChange()
+}
+
+func (r *CachedRequestStore) ListRequests() ([]Request, error) {
+	r.mu.Lock()
+	loaded := r.loaded
+	r.mu.Unlock()
+	if !loaded {
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request(nil), r.requests...), nil
+}
+
+func (r *CachedRequestStore) GetRequest(id string) (Request, error) {
+	requests, err := r.ListRequests()
+	if err != nil {
+		return Request{}, err
+	}
+	for _, req := range requests {
+		if req.ID == id {
+			return req.Copy(), nil
+		}
+	}
+	return r.RequestStorage.GetRequest(id)
+}
+
+func (r *CachedRequestStore) CreateRequest(req Request) error {
+	if err := r.RequestStorage.CreateRequest(req); err != nil {
+		return err
+	}
+	req = req.Copy()
+	r.update(func(requests []Request) []Request {
+		for i := range requests {
+			if requests[i].ID == req.ID {
+				requests[i] = req
+				return requests
+			}
+		}
+		return append(requests, req)
+	})
+	return nil
+}
+
+func (r *CachedRequestStore) UpdateRequest(req Request) error {
+	return r.CreateRequest(req)
+}
+
+func (r *CachedRequestStore) DeleteRequest(id string) error {
+	if err := r.RequestStorage.DeleteRequest(id); err != nil {
+		return err
+	}
+	r.update(func(requests []Request) []Request {
+		for i := range requests {
+			if requests[i].ID == id {
+				return append(requests[:i], requests[i+1:]...)
+			}
+		}
+		return requests
+	})
+	return nil
+}
+
+func (r *CachedRequestStore) WriteCatalog(catalog []string) error {
+	if err := r.RequestStorage.WriteCatalog(catalog); err != nil {
+		return err
+	}
+	r.update(func(requests []Request) []Request {
+		return sortRequestsByCatalog(requests, catalog)
+	})
+	return nil
+}
+
+// Sync reloads the requests if they were changed outside of this cache.
+func (r *CachedRequestStore) Sync() error {
+	r.mu.Lock()
+	loaded, token := r.loaded, r.token
+	r.mu.Unlock()
+	if !loaded {
+		return nil
+	}
+	if !changedSince(r.RequestStorage, token) {
+		return nil
+	}
+	return r.load()
+}
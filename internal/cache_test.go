@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// cacheBackends opens a workspace twice with a storage backend that can detect
+// changes, to edit it behind the back of a cache
+var cacheBackends = []struct {
+	name string
+	open func(t *testing.T) (cached, other Storage)
+}{
+	{FileStorageBackend, func(t *testing.T) (Storage, Storage) {
+		root := filepath.Join(t.TempDir(), "workspace")
+		s, err := NewCollectionStore(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := NewCollectionStore(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s, other
+	}},
+	{SQLiteStorageBackend, func(t *testing.T) (Storage, Storage) {
+		root := t.TempDir()
+		s, err := NewSQLiteStorage(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		other, err := NewSQLiteStorage(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { other.Close() })
+		return s, other
+	}},
+}
+
+func TestCachedStorageSync(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit changes the workspace holding the request a without the cache
+		edit            func(s Storage) error
+		wantCollections []string
+		wantRequests    []string
+		// whether the cache picks up a change
+		wantChanged bool
+	}{
+		{
+			name:            "unchanged",
+			edit:            func(s Storage) error { return nil },
+			wantCollections: []string{DEFAULT_COLLECTION_NAME},
+			wantRequests:    []string{"a"},
+		},
+		{
+			name: "request created",
+			edit: func(s Storage) error {
+				store, err := s.Requests(DEFAULT_COLLECTION_NAME)
+				if err != nil {
+					return err
+				}
+				return store.CreateRequest(Request{ID: "b", Method: "GET"})
+			},
+			wantCollections: []string{DEFAULT_COLLECTION_NAME},
+			wantRequests:    []string{"a", "b"},
+			wantChanged:     true,
+		},
+		{
+			name: "request deleted",
+			edit: func(s Storage) error {
+				store, err := s.Requests(DEFAULT_COLLECTION_NAME)
+				if err != nil {
+					return err
+				}
+				return store.DeleteRequest("a")
+			},
+			wantCollections: []string{DEFAULT_COLLECTION_NAME},
+			wantRequests:    []string{},
+			wantChanged:     true,
+		},
+		{
+			name:            "collection created",
+			edit:            func(s Storage) error { return s.CreateCollection("other") },
+			wantCollections: []string{DEFAULT_COLLECTION_NAME, "other"},
+			wantRequests:    []string{"a"},
+			wantChanged:     true,
+		},
+	}
+	for _, backend := range cacheBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				s, other := backend.open(t)
+				c := NewCachedStorage(s)
+				store, err := c.Requests(DEFAULT_COLLECTION_NAME)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := store.CreateRequest(Request{ID: "a", Method: "GET"}); err != nil {
+					t.Fatal(err)
+				}
+				// load both listings into the cache
+				if _, err := c.ListCollections(); err != nil {
+					t.Fatal(err)
+				}
+				requestIDs(t, store)
+				version := c.Version()
+
+				if err := tt.edit(other); err != nil {
+					t.Fatal(err)
+				}
+				if err := c.Sync(); err != nil {
+					t.Fatal(err)
+				}
+				if got, _ := c.ListCollections(); !reflect.DeepEqual(got, tt.wantCollections) {
+					t.Errorf("collections = %q, want %q", got, tt.wantCollections)
+				}
+				if got := requestIDs(t, store); !reflect.DeepEqual(got, tt.wantRequests) {
+					t.Errorf("requests = %q, want %q", got, tt.wantRequests)
+				}
+				if changed := c.Version() != version; changed != tt.wantChanged {
+					t.Errorf("version changed = %v", changed)
+				}
+			})
+		}
+	}
+}
+
+func TestCachedStorageWriteThrough(t *testing.T) {
+	s := NewMemoryStorage()
+	c := NewCachedStorage(s)
+	store, err := c.Requests(DEFAULT_COLLECTION_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := store.CreateRequest(Request{ID: id, Method: "GET"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := requestIDs(t, store)
+	if err := store.WriteCatalog([]string{"b", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(before, want) {
+		t.Errorf("an earlier listing changed to %q", before)
+	}
+	if got, want := requestIDs(t, store), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached requests = %q, want %q", got, want)
+	}
+	inner, err := s.Requests(DEFAULT_COLLECTION_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := requestIDs(t, inner), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stored requests = %q, want %q", got, want)
+	}
+}
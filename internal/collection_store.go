@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"gopkg.in/yaml.v3"
 )
 
 const DEFAULT_COLLECTION_NAME = "default"
 
 // default: ~/.agora
-func defaultRootDir() (string, error) {
+func DefaultRootDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -17,6 +19,8 @@ func defaultRootDir() (string, error) {
 	return filepath.Join(home, ".agora"), nil
 }
 
+// File store of a workspace.
+// Each collection is a directory and each request is a YAML file.
 type CollectionStore struct {
 	root              string
 	currentCollection string
@@ -29,29 +33,12 @@ func NewCollectionStore(root string) (*CollectionStore, error) {
 	}
 
 	c := &CollectionStore{root: root}
-	collections, err := c.ListCollections()
-	if err != nil {
+	if err := initCurrentCollection(c); err != nil {
 		return nil, err
 	}
-	if len(collections) > 0 {
-		c.currentCollection = collections[0]
-	} else {
-		if err := c.CreateCollection(c.currentCollection); err != nil && !os.IsExist(err) {
-			return nil, err
-		}
-		c.currentCollection = DEFAULT_COLLECTION_NAME
-	}
 	return c, nil
 }
 
-func NewDefaultCollectionStore() (*CollectionStore, error) {
-	dir, err := defaultRootDir()
-	if err != nil {
-		return nil, err
-	}
-	return NewCollectionStore(dir)
-}
-
 func (c *CollectionStore) Root() string {
 	return c.root
 }
@@ -61,13 +48,12 @@ func (c *CollectionStore) SetRoot(dir string) {
 }
 
 func (c *CollectionStore) GetFirstCollection() (string, error) {
-	collectionsDir := filepath.Join(c.Root(), "collections")
-	entries, err := os.ReadDir(collectionsDir)
+	collections, err := c.ListCollections()
 	if err != nil {
 		return "", err
 	}
-	for _, entry := range entries {
-		return entry.Name(), nil
+	for _, collection := range collections {
+		return collection, nil
 	}
 	return "", fmt.Errorf("no collections found")
 }
@@ -84,7 +70,41 @@ func (c *CollectionStore) ListCollections() ([]string, error) {
 			dirs = append(dirs, entry.Name())
 		}
 	}
-	return dirs, nil
+	catalog, err := c.readCollectionCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return sortByCatalog(dirs, catalog), nil
+}
+
+// Collection catalog file is a list of collection names
+// and maintains the order of collections
+
+func (c *CollectionStore) calcCollectionCatalogFilename() string {
+	return filepath.Join(c.Root(), "collections", ".catalog")
+}
+
+func (c *CollectionStore) readCollectionCatalog() ([]string, error) {
+	data, err := os.ReadFile(c.calcCollectionCatalogFilename())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var catalog []string
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func (c *CollectionStore) WriteCollectionCatalog(catalog []string) error {
+	data, err := yaml.Marshal(catalog)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.calcCollectionCatalogFilename(), data, 0644)
 }
 
 func (c *CollectionStore) CollectionDir(collection string) string {
@@ -105,7 +125,23 @@ func (c *CollectionStore) DeleteCollection(collection string) error {
 }
 
 func (c *CollectionStore) RenameCollection(oldName, newName string) error {
-	return os.Rename(c.CollectionDir(oldName), c.CollectionDir(newName))
+	if err := os.Rename(c.CollectionDir(oldName), c.CollectionDir(newName)); err != nil {
+		return err
+	}
+	catalog, err := c.readCollectionCatalog()
+	if err != nil || catalog == nil {
+		return err
+	}
+	for i, name := range catalog {
+		if name == oldName {
+			catalog[i] = newName
+		}
+	}
+	return c.WriteCollectionCatalog(catalog)
+}
+
+func (c *CollectionStore) DuplicateCollection(src, dst string) error {
+	return duplicateCollection(c, src, dst)
 }
 
 func (c *CollectionStore) CurrentCollection() string {
@@ -121,5 +157,21 @@ func (c *CollectionStore) CurrentCollectionDir() string {
 }
 
 func (c *CollectionStore) CurrentCollectionRequestDir() string {
-	return filepath.Join(c.CurrentCollectionDir(), "requests")
+	return c.CollectionRequestDir(c.currentCollection)
+}
+
+func (c *CollectionStore) CollectionRequestDir(collection string) string {
+	return filepath.Join(c.CollectionDir(collection), "requests")
+}
+
+func (c *CollectionStore) Requests(collection string) (RequestStorage, error) {
+	return NewRequestFileStore(c.CollectionRequestDir(collection))
+}
+
+func (c *CollectionStore) Close() error {
+	return nil
+}
+
+func (c *CollectionStore) ChangeToken() (string, error) {
+	return dirChangeToken(filepath.Join(c.Root(), "collections"))
 }
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ContentKind is how the body of a response is rendered
+type ContentKind int
+
+const (
+	ContentText ContentKind = iota
+	ContentJson
+	ContentXml
+	ContentHtml
+	ContentYaml
+	ContentCsv
+	ContentTsv
+	ContentImage
+	ContentBinary
+)
+
+// MediaType returns the media type of the response in lower case and its
+// parameters, such as the charset. Without a Content-Type header, the
+// media type is sniffed from the content.
+func (r Response) MediaType() (string, map[string]string) {
+	contentType := r.ContentType()
+	if contentType == "" {
+		contentType = http.DetectContentType(r.Content)
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// keep what is before the parameters of a malformed header
+		mediaType, _, _ = strings.Cut(contentType, ";")
+		return strings.ToLower(strings.TrimSpace(mediaType)), map[string]string{}
+	}
+	return mediaType, params
+}
+
+// Kind returns how the body should be rendered, by its media type
+func (r Response) Kind() ContentKind {
+	mediaType, _ := r.MediaType()
+	main, sub, _ := strings.Cut(mediaType, "/")
+	switch {
+	case sub == "json" || strings.HasSuffix(sub, "+json"):
+		return ContentJson
+	case sub == "html" || sub == "xhtml+xml":
+		return ContentHtml
+	case sub == "xml" || strings.HasSuffix(sub, "+xml"):
+		return ContentXml
+	case sub == "yaml" || sub == "x-yaml" || strings.HasSuffix(sub, "+yaml"):
+		return ContentYaml
+	case mediaType == "text/csv":
+		return ContentCsv
+	case mediaType == "text/tab-separated-values":
+		return ContentTsv
+	case main == "image":
+		return ContentImage
+	case main == "text", isText(r.preview()):
+		return ContentText
+	default:
+		return ContentBinary
+	}
+}
+
+// isText reports whether content looks like text,
+// for media types such as application/javascript
+func isText(content []byte) bool {
+	return utf8.Valid(content) && !bytes.ContainsRune(content, 0)
+}
+
+// Text returns the content decoded from the charset of the response
+func (r Response) Text() (string, error) {
+	_, params := r.MediaType()
+	charset := params["charset"]
+	content := r.preview()
+	if charset == "" || strings.EqualFold(charset, "utf-8") {
+		return string(content), nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return string(content), fmt.Errorf("unsupported charset %q", charset)
+	}
+	b, err := io.ReadAll(encoding.NewDecoder().Reader(bytes.NewReader(content)))
+	if err != nil {
+		return string(content), err
+	}
+	return string(b), nil
+}
+
+// ImageConfig returns the format and the dimensions of an image,
+// for the formats supported by the standard library
+func (r Response) ImageConfig() (image.Config, string, error) {
+	return image.DecodeConfig(bytes.NewReader(r.Content))
+}
+
+// FormatSize formats a number of bytes, e.g. 1.5 KiB
+func FormatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	var suffix string
+	for _, suffix = range []string{"KiB", "MiB", "GiB"} {
+		value /= 1024
+		if value < 1024 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,31 @@
+package internal
+
+import "strings"
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Curl returns a curl command that sends the request
+func (r Request) Curl() string {
+	parts := []string{"curl"}
+	switch {
+	case r.Method == "HEAD":
+		// -X HEAD would wait for a body that never comes
+		parts = append(parts, "--head")
+	case r.Method != "" && r.Method != "GET":
+		parts = append(parts, "-X", r.Method)
+	case len(r.Body) > 0:
+		// curl sends a POST when there is data
+		parts = append(parts, "-X", "GET")
+	}
+	parts = append(parts, shellQuote(r.ResolvedURL()))
+	for _, kv := range r.Headers.Enabled() {
+		parts = append(parts, "-H", shellQuote(kv.Key+": "+kv.Value))
+	}
+	if len(r.Body) > 0 {
+		parts = append(parts, "--data-raw", shellQuote(string(r.Body)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Bodies larger than LargeBodySize are written to a temporary file as they
+// are received, and only their first BodyPreviewSize bytes are kept in memory.
+const (
+	LargeBodySize   = 2 << 20
+	BodyPreviewSize = 256 << 10
+)
+
+// progressInterval limits how often the progress of a download is reported
+const progressInterval = 100 * time.Millisecond
+
+// ReadResponse reads the body of a response. progress is called with the
+// number of bytes received and the Content-Length, which is -1 if unknown.
+func ReadResponse(resp *http.Response, progress func(received, total int64)) (*Response, error) {
+	defer resp.Body.Close()
+
+	var headers KVPairs = make([]KVPair, 0)
+	for k, v := range resp.Header {
+		headers = headers.Add(k, strings.Join(v, ", "))
+	}
+
+	var (
+		preview  bytes.Buffer
+		file     *os.File
+		received int64
+		reported time.Time
+	)
+	fail := func(err error) (*Response, error) {
+		if file != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+		return nil, err
+	}
+	chunk := make([]byte, 32<<10)
+	for {
+		n, readErr := resp.Body.Read(chunk)
+		if n > 0 {
+			received += int64(n)
+			if file == nil && preview.Len()+n > LargeBodySize {
+				var err error
+				if file, err = os.CreateTemp("", "agora-body-*"); err != nil {
+					return fail(err)
+				}
+				if _, err := file.Write(preview.Bytes()); err != nil {
+					return fail(err)
+				}
+				preview.Truncate(BodyPreviewSize)
+			}
+			if file != nil {
+				if _, err := file.Write(chunk[:n]); err != nil {
+					return fail(err)
+				}
+				preview.Write(chunk[:max(0, min(n, BodyPreviewSize-preview.Len()))])
+			} else {
+				preview.Write(chunk[:n])
+			}
+			if progress != nil && time.Since(reported) >= progressInterval {
+				progress(received, resp.ContentLength)
+				reported = time.Now()
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fail(readErr)
+		}
+	}
+
+	r := NewResponse(resp.StatusCode, preview.Bytes(), headers)
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return fail(err)
+		}
+		r.BodyFile = file.Name()
+		r.Size = received
+	}
+	return r, nil
+}
+
+// Truncated reports whether Content is only a preview of the body,
+// which is kept in BodyFile
+func (r Response) Truncated() bool {
+	return r.BodyFile != ""
+}
+
+// preview returns the content without a rune cut at the end of a preview
+func (r Response) preview() []byte {
+	content := r.Content
+	if !r.Truncated() {
+		return content
+	}
+	for i := 0; i < utf8.UTFMax && len(content) > 0; i++ {
+		if ru, size := utf8.DecodeLastRune(content); ru != utf8.RuneError || size != 1 {
+			break
+		}
+		content = content[:len(content)-1]
+	}
+	return content
+}
+
+// SaveBody writes the whole body to a file
+func (r Response) SaveBody(path string) error {
+	if r.Truncated() {
+		return copyFile(r.BodyFile, path)
+	}
+	return os.WriteFile(path, r.Content, 0644)
+}
+
+// Discard removes the file of a large body
+func (r Response) Discard() {
+	if r.Truncated() {
+		os.Remove(r.BodyFile)
+	}
+}
+
+// FileName suggests a name to save the body to, from the Content-Disposition
+// header or the URL of the request
+func (r Response) FileName(requestURL string) string {
+	for _, kv := range r.Headers {
+		if strings.EqualFold(kv.Key, "Content-Disposition") {
+			if _, params, err := mime.ParseMediaType(kv.Value); err == nil && params["filename"] != "" {
+				return path.Base(params["filename"])
+			}
+		}
+	}
+	if u, err := url.Parse(requestURL); err == nil {
+		if name := path.Base(u.Path); name != "." && name != "/" && name != "" {
+			return name
+		}
+	}
+	mediaType, _ := r.MediaType()
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return "response" + extensions[0]
+	}
+	return "response"
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		// whether the body is kept in a temporary file
+		wantFile    bool
+		wantContent int
+	}{
+		{"empty", 0, false, 0},
+		{"small", 1 << 10, false, 1 << 10},
+		{"at the threshold", LargeBodySize, false, LargeBodySize},
+		{"above the threshold", LargeBodySize + 1, true, BodyPreviewSize},
+		{"large", 3*LargeBodySize + 123, true, BodyPreviewSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := bytes.Repeat([]byte("0123456789abcdef"), tt.size/16+1)[:tt.size]
+			resp := &http.Response{
+				StatusCode:    http.StatusOK,
+				Header:        http.Header{"Content-Type": {"text/plain"}},
+				Body:          io.NopCloser(bytes.NewReader(body)),
+				ContentLength: int64(tt.size),
+			}
+			var received int64
+			r, err := ReadResponse(resp, func(n, total int64) { received = n })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Discard()
+
+			if r.Truncated() != tt.wantFile {
+				t.Errorf("Truncated() = %v, want %v", r.Truncated(), tt.wantFile)
+			}
+			if len(r.Content) != tt.wantContent || !bytes.Equal(r.Content, body[:len(r.Content)]) {
+				t.Errorf("Content is %d bytes, want the first %d bytes of the body", len(r.Content), tt.wantContent)
+			}
+			if r.Size != int64(tt.size) {
+				t.Errorf("Size = %d, want %d", r.Size, tt.size)
+			}
+			if tt.size > 0 && received == 0 {
+				t.Error("progress was not reported")
+			}
+
+			saved := filepath.Join(t.TempDir(), "body")
+			if err := r.SaveBody(saved); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := os.ReadFile(saved); err != nil || !bytes.Equal(data, body) {
+				t.Errorf("saved %d bytes, %v, want the whole body of %d bytes", len(data), err, len(body))
+			}
+			if tt.wantFile {
+				file := r.BodyFile
+				r.Discard()
+				if _, err := os.Stat(file); !os.IsNotExist(err) {
+					t.Errorf("%s was not removed: %v", file, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResponsePreview(t *testing.T) {
+	r := Response{Content: []byte("ab\xe2\x82"), BodyFile: "body"}
+	if got := string(r.preview()); got != "ab" {
+		t.Errorf("preview() = %q, want the rune cut at the end dropped", got)
+	}
+	r.BodyFile = ""
+	if got := string(r.preview()); got != "ab\xe2\x82" {
+		t.Errorf("preview() = %q, want the whole content", got)
+	}
+}
+
+func TestResponseFileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers KVPairs
+		url     string
+		want    string
+	}{
+		{"content disposition", KVPairs{}.Add("Content-Disposition", `attachment; filename="../report.csv"`), "http://x/download", "report.csv"},
+		{"url path", nil, "http://x/files/data.json?v=1", "data.json"},
+		{"media type", KVPairs{}.Add("Content-Type", "application/json"), "http://x/", "response.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResponse(http.StatusOK, nil, tt.headers)
+			if got := r.FileName(tt.url); got != tt.want {
+				t.Errorf("FileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
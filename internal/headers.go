@@ -0,0 +1,109 @@
+package internal
+
+import "strings"
+
+// commonHeaders are the request headers suggested when adding a header
+var commonHeaders = []string{
+	"Accept",
+	"Accept-Charset",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Authorization",
+	"Cache-Control",
+	"Connection",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Length",
+	"Content-Type",
+	"Cookie",
+	"DNT",
+	"Expect",
+	"Forwarded",
+	"From",
+	"Host",
+	"If-Match",
+	"If-Modified-Since",
+	"If-None-Match",
+	"If-Range",
+	"If-Unmodified-Since",
+	"Origin",
+	"Pragma",
+	"Prefer",
+	"Proxy-Authorization",
+	"Range",
+	"Referer",
+	"TE",
+	"Upgrade",
+	"User-Agent",
+	"Via",
+	"X-API-Key",
+	"X-Correlation-ID",
+	"X-Forwarded-For",
+	"X-Forwarded-Host",
+	"X-Forwarded-Proto",
+	"X-Request-ID",
+	"X-Requested-With",
+}
+
+var mediaTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/x-www-form-urlencoded",
+	"multipart/form-data",
+	"application/octet-stream",
+	"text/plain",
+	"text/html",
+	"text/csv",
+}
+
+// commonHeaderValues are the values suggested for a header
+var commonHeaderValues = map[string][]string{
+	"Accept":           append([]string{"*/*"}, mediaTypes...),
+	"Accept-Encoding":  {"gzip", "deflate", "br", "gzip, deflate, br", "identity"},
+	"Accept-Language":  {"en-US", "en", "*"},
+	"Authorization":    {"Bearer ", "Basic "},
+	"Cache-Control":    {"no-cache", "no-store", "max-age=0", "must-revalidate"},
+	"Connection":       {"keep-alive", "close"},
+	"Content-Encoding": {"gzip", "deflate", "br"},
+	"Content-Type":     mediaTypes,
+	"Expect":           {"100-continue"},
+	"Pragma":           {"no-cache"},
+	"Prefer":           {"return=minimal", "return=representation", "respond-async"},
+	"TE":               {"trailers"},
+	"Upgrade":          {"websocket"},
+	"User-Agent":       {"agora"},
+	"X-Requested-With": {"XMLHttpRequest"},
+}
+
+// HeaderNameSuggestions returns the common header names, followed by the
+// other names used by the requests, without duplicates in any case
+func HeaderNameSuggestions(requests []Request) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range commonHeaders {
+		add(name)
+	}
+	for _, r := range requests {
+		for _, kv := range r.Headers {
+			add(kv.Key)
+		}
+	}
+	return names
+}
+
+// HeaderValueSuggestions returns the common values of a header
+func HeaderValueSuggestions(name string) []string {
+	for header, values := range commonHeaderValues {
+		if strings.EqualFold(header, name) {
+			return values
+		}
+	}
+	return nil
+}
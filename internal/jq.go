@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itchyny/gojq"
+)
+
+// jqTimeout stops filters that do not terminate, e.g. `repeat(.)`
+const jqTimeout = 2 * time.Second
+
+// FilterJson applies a jq filter to a JSON document and returns
+// each result as compact JSON.
+func FilterJson(data []byte, filter string) ([]string, error) {
+	query, err := gojq.Parse(filter)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var input any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&input); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jqTimeout)
+	defer cancel()
+	var results []string
+	iter := code.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			return nil, err
+		}
+		b, err := gojq.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, string(b))
+	}
+	return results, nil
+}
@@ -0,0 +1,50 @@
+package internal
+
+import "strings"
+
+// disabledPrefix starts the line of a disabled pair
+const disabledPrefix = "#"
+
+// FormatKVPairs writes the pairs one per line, with the key and the value
+// joined by separator, e.g. "Key: Value" for headers or "key=value" for params.
+// Disabled pairs are commented out with a leading "#", and bare pairs
+// without a value are written as their key only.
+func FormatKVPairs(kvs KVPairs, separator string) string {
+	var b strings.Builder
+	for _, kv := range kvs {
+		if !kv.Enabled {
+			b.WriteString(disabledPrefix)
+		}
+		b.WriteString(kv.Key)
+		if !kv.Bare || kv.Value != "" {
+			b.WriteString(separator)
+			b.WriteString(kv.Value)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ParseKVPairs reads the pairs written by FormatKVPairs, in order and with
+// duplicates. Each line is split at the first occurrence of separator, and
+// the key and the value are trimmed. A line without the separator is a bare
+// key with an empty value. Blank lines and lines without a key, such as HTTP/2
+// pseudo-headers like ":authority: host", are skipped. A leading "#"
+// disables the pair.
+func ParseKVPairs(text, separator string) KVPairs {
+	kvs := make(KVPairs, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line, disabled := strings.CutPrefix(line, disabledPrefix)
+		key, value, found := strings.Cut(line, strings.TrimSpace(separator))
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+		kvs = append(kvs, KVPair{Key: key, Value: value, Enabled: !disabled, Bare: !found})
+	}
+	return kvs
+}
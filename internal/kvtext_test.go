@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKVPairs(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		separator string
+		want      KVPairs
+	}{
+		{
+			name:      "headers",
+			text:      "Accept: application/json\nAuthorization: Bearer a:b\n",
+			separator: ": ",
+			want: KVPairs{
+				{Key: "Accept", Value: "application/json", Enabled: true},
+				{Key: "Authorization", Value: "Bearer a:b", Enabled: true},
+			},
+		},
+		{
+			name:      "params split at the first separator",
+			text:      "filter=a=b\nq=\n",
+			separator: "=",
+			want: KVPairs{
+				{Key: "filter", Value: "a=b", Enabled: true},
+				{Key: "q", Value: "", Enabled: true},
+			},
+		},
+		{
+			name:      "blank lines and spaces",
+			text:      "\n  page = 2  \n\n",
+			separator: "=",
+			want:      KVPairs{{Key: "page", Value: "2", Enabled: true}},
+		},
+		{
+			name:      "disabled",
+			text:      "#debug=1\n# trace=2\n",
+			separator: "=",
+			want: KVPairs{
+				{Key: "debug", Value: "1", Enabled: false},
+				{Key: "trace", Value: "2", Enabled: false},
+			},
+		},
+		{
+			name:      "without separator",
+			text:      "flag\n",
+			separator: "=",
+			want:      KVPairs{{Key: "flag", Value: "", Enabled: true, Bare: true}},
+		},
+		{
+			name:      "pseudo-headers are skipped",
+			text:      ":authority: example.com\n:method: GET\nHost: example.com\n",
+			separator: ": ",
+			want:      KVPairs{{Key: "Host", Value: "example.com", Enabled: true}},
+		},
+		{
+			name:      "empty keys are skipped",
+			text:      "=1\n#=2\n",
+			separator: "=",
+			want:      KVPairs{},
+		},
+		{
+			name:      "duplicates in order",
+			text:      "a=1\nb=2\na=3\n",
+			separator: "=",
+			want: KVPairs{
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "b", Value: "2", Enabled: true},
+				{Key: "a", Value: "3", Enabled: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseKVPairs(tt.text, tt.separator)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKVPairs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatKVPairsRoundTrip(t *testing.T) {
+	kvs := KVPairs{
+		{Key: "a", Value: "1", Enabled: true},
+		{Key: "empty", Value: "", Enabled: true},
+		{Key: "flag", Value: "", Enabled: true, Bare: true},
+		{Key: "off", Value: "x", Enabled: false},
+	}
+	text := FormatKVPairs(kvs, "=")
+	if want := "a=1\nempty=\nflag\n#off=x\n"; text != want {
+		t.Errorf("FormatKVPairs() = %q, want %q", text, want)
+	}
+	if got := ParseKVPairs(text, "="); !reflect.DeepEqual(got, kvs) {
+		t.Errorf("ParseKVPairs() = %+v, want %+v", got, kvs)
+	}
+}
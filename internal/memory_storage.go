@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// In-memory store of a workspace.
+// Nothing is persisted; useful for tests and scratch sessions.
+type MemoryStorage struct {
+	mu                sync.Mutex
+	collections       []string
+	requests          map[string]*MemoryRequestStore
+	currentCollection string
+	trash             []TrashItem
+	settings          map[string]string
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	s := &MemoryStorage{
+		requests: make(map[string]*MemoryRequestStore),
+		settings: make(map[string]string),
+	}
+	// cannot fail for in-memory storage
+	_ = initCurrentCollection(s)
+	return s
+}
+
+func (s *MemoryStorage) indexOfCollection(collection string) int {
+	for i, c := range s.collections {
+		if c == collection {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *MemoryStorage) ListCollections() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.collections...), nil
+}
+
+func (s *MemoryStorage) GetFirstCollection() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.collections) == 0 {
+		return "", fmt.Errorf("no collections found")
+	}
+	return s.collections[0], nil
+}
+
+func (s *MemoryStorage) CollectionExists(collection string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.indexOfCollection(collection) >= 0
+}
+
+func (s *MemoryStorage) CreateCollection(collection string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indexOfCollection(collection) >= 0 {
+		return nil
+	}
+	s.collections = append(s.collections, collection)
+	s.requests[collection] = NewMemoryRequestStore()
+	return nil
+}
+
+func (s *MemoryStorage) DeleteCollection(collection string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.indexOfCollection(collection)
+	if i < 0 {
+		return nil
+	}
+	s.collections = append(s.collections[:i], s.collections[i+1:]...)
+	delete(s.requests, collection)
+	return nil
+}
+
+func (s *MemoryStorage) RenameCollection(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.indexOfCollection(oldName)
+	if i < 0 {
+		return fmt.Errorf("collection not found: %s", oldName)
+	}
+	if s.indexOfCollection(newName) >= 0 {
+		return fmt.Errorf("collection already exists: %s", newName)
+	}
+	s.collections[i] = newName
+	s.requests[newName] = s.requests[oldName]
+	delete(s.requests, oldName)
+	return nil
+}
+
+func (s *MemoryStorage) DuplicateCollection(src, dst string) error {
+	return duplicateCollection(s, src, dst)
+}
+
+func (s *MemoryStorage) WriteCollectionCatalog(catalog []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections = sortByCatalog(s.collections, catalog)
+	return nil
+}
+
+func (s *MemoryStorage) CurrentCollection() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentCollection
+}
+
+func (s *MemoryStorage) SetCurrentCollection(collection string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currentCollection = collection
+}
+
+func (s *MemoryStorage) Requests(collection string) (RequestStorage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.requests[collection]
+	if !ok {
+		return nil, fmt.Errorf("collection not found: %s", collection)
+	}
+	return store, nil
+}
+
+func (s *MemoryStorage) GetSetting(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings[key], nil
+}
+
+func (s *MemoryStorage) SetSetting(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == "" {
+		delete(s.settings, key)
+	} else {
+		s.settings[key] = value
+	}
+	return nil
+}
+
+func (s *MemoryStorage) AddToTrash(item TrashItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trash = append([]TrashItem{item}, s.trash...)
+	return nil
+}
+
+func (s *MemoryStorage) ListTrash() ([]TrashItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TrashItem(nil), s.trash...), nil
+}
+
+func (s *MemoryStorage) RemoveFromTrash(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, item := range s.trash {
+		if item.ID == id {
+			s.trash = append(s.trash[:i], s.trash[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("trash item not found: %s", id)
+}
+
+func (s *MemoryStorage) Close() error {
+	return nil
+}
+
+// In-memory store of a single collection
+type MemoryRequestStore struct {
+	mu       sync.Mutex
+	requests map[string]Request
+	catalog  []string
+}
+
+func NewMemoryRequestStore() *MemoryRequestStore {
+	return &MemoryRequestStore{requests: make(map[string]Request)}
+}
+
+func (r *MemoryRequestStore) CreateRequest(req Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.requests[req.ID]; !ok {
+		r.catalog = append(r.catalog, req.ID)
+	}
+	r.requests[req.ID] = req
+	return nil
+}
+
+func (r *MemoryRequestStore) GetRequest(id string) (Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.requests[id]
+	if !ok {
+		return Request{}, fmt.Errorf("request not found: %s", id)
+	}
+	return req, nil
+}
+
+func (r *MemoryRequestStore) ListRequests() ([]Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	requests := make([]Request, 0, len(r.catalog))
+	for _, id := range r.catalog {
+		requests = append(requests, r.requests[id])
+	}
+	return requests, nil
+}
+
+func (r *MemoryRequestStore) UpdateRequest(req Request) error {
+	return r.CreateRequest(req)
+}
+
+func (r *MemoryRequestStore) DeleteRequest(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.requests[id]; !ok {
+		return fmt.Errorf("request not found: %s", id)
+	}
+	delete(r.requests, id)
+	for i, v := range r.catalog {
+		if v == id {
+			r.catalog = append(r.catalog[:i], r.catalog[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRequestStore) ReadCatalog() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.catalog...), nil
+}
+
+func (r *MemoryRequestStore) WriteCatalog(catalog []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// keep only known requests, and append any missing ones
+	seen := make(map[string]bool, len(catalog))
+	var newCatalog []string
+	for _, id := range catalog {
+		if _, ok := r.requests[id]; ok && !seen[id] {
+			seen[id] = true
+			newCatalog = append(newCatalog, id)
+		}
+	}
+	for _, id := range r.catalog {
+		if !seen[id] {
+			newCatalog = append(newCatalog, id)
+		}
+	}
+	r.catalog = newCatalog
+	return nil
+}
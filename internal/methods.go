@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StandardMethods are the methods offered for every request
+var StandardMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// ExtendedMethods are well-known methods other than the standard ones,
+// of WebDAV, caches and the QUERY draft, suggested when typing a method
+var ExtendedMethods = []string{
+	"CONNECT", "TRACE", "QUERY", "PURGE",
+	"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK", "REPORT", "SEARCH",
+}
+
+// the custom methods of a workspace are saved in this setting, separated by spaces
+const customMethodsSetting = "custom_methods"
+
+// isTokenChar reports whether c may appear in a method, which is a token of RFC 9110
+func isTokenChar(c rune) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", c)
+}
+
+// NormalizeMethod upper-cases a method typed by the user and checks that it is valid
+func NormalizeMethod(method string) (string, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "" {
+		return "", fmt.Errorf("method is empty")
+	}
+	for _, c := range method {
+		if !isTokenChar(c) {
+			return "", fmt.Errorf("invalid character %q", c)
+		}
+	}
+	return method, nil
+}
+
+// CustomMethods returns the methods other than the standard ones
+// used in the workspace, in the order they were added
+func CustomMethods(s SettingsStorage) ([]string, error) {
+	value, err := s.GetSetting(customMethodsSetting)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(value), nil
+}
+
+// AddCustomMethod remembers a method of the workspace, unless it is a standard one
+func AddCustomMethod(s SettingsStorage, method string) error {
+	if slices.Contains(StandardMethods, method) {
+		return nil
+	}
+	methods, err := CustomMethods(s)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(methods, method) {
+		return nil
+	}
+	return s.SetSetting(customMethodsSetting, strings.Join(append(methods, method), " "))
+}
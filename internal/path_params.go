@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Path params are the `:name` and `{name}` segments in the path of a URL.
+// Their values are kept in PathParams, and substituted when the request
+// is sent. Double braces, like `{{name}}`, are not path params.
+
+var pathParamPattern = regexp.MustCompile(`:[A-Za-z_][A-Za-z0-9_]*|\{+[A-Za-z_][A-Za-z0-9_.-]*\}+`)
+
+type pathParamMatch struct {
+	start, end int
+	name       string
+}
+
+// pathStart returns the index of the path in a URL without its query,
+// after the scheme and the host
+func pathStart(base string) int {
+	authority := 0
+	if i := strings.Index(base, "://"); i >= 0 {
+		authority = i + len("://")
+	}
+	if i := strings.IndexByte(base[authority:], '/'); i >= 0 {
+		return authority + i
+	}
+	return len(base)
+}
+
+// findPathParams returns the path params in a URL without its query, in order
+func findPathParams(base string) []pathParamMatch {
+	start := pathStart(base)
+	var matches []pathParamMatch
+	for _, loc := range pathParamPattern.FindAllStringIndex(base[start:], -1) {
+		s := base[start+loc[0] : start+loc[1]]
+		var name string
+		if strings.HasPrefix(s, ":") {
+			// a colon only starts a param at the beginning of a segment
+			if base[start+loc[0]-1] != '/' {
+				continue
+			}
+			name = s[1:]
+		} else {
+			name = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+			if strings.ContainsAny(name, "{}") {
+				continue
+			}
+		}
+		matches = append(matches, pathParamMatch{start + loc[0], start + loc[1], name})
+	}
+	return matches
+}
+
+// PathParamNames returns the names of the path params in a URL,
+// in order and without duplicates
+func PathParamNames(rawURL string) []string {
+	base, _, _ := splitURL(rawURL)
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range findPathParams(base) {
+		if !seen[m.name] {
+			seen[m.name] = true
+			names = append(names, m.name)
+		}
+	}
+	return names
+}
+
+// pathParamValue returns the value of a path param, if set
+func (r Request) pathParamValue(name string) (string, bool) {
+	for _, kv := range r.PathParams {
+		if kv.Key == name {
+			return kv.Value, true
+		}
+	}
+	return "", false
+}
+
+// PathParamValues returns the path params of the URL with their values
+func (r Request) PathParamValues() KVPairs {
+	kvs := make(KVPairs, 0)
+	for _, name := range PathParamNames(r.URL) {
+		value, _ := r.pathParamValue(name)
+		kvs = kvs.Add(name, value)
+	}
+	return kvs
+}
+
+// SetPathParam sets the value of a path param
+func (r *Request) SetPathParam(name, value string) {
+	for i, kv := range r.PathParams {
+		if kv.Key == name {
+			r.PathParams[i].Value = value
+			return
+		}
+	}
+	r.PathParams = r.PathParams.Add(name, value)
+}
+
+// syncPathParams keeps the values of the path params still in the URL
+func (r *Request) syncPathParams() {
+	if len(r.PathParams) == 0 {
+		return
+	}
+	r.PathParams = r.PathParamValues()
+}
+
+// expandPath substitutes the path params of a URL without its query
+// with their URL-escaped values. Params without a value are left as they are.
+func (r Request) expandPath(base string) string {
+	matches := findPathParams(base)
+	if len(matches) == 0 {
+		return base
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		value, ok := r.pathParamValue(m.name)
+		if !ok || value == "" {
+			continue
+		}
+		b.WriteString(base[last:m.start])
+		b.WriteString(url.PathEscape(value))
+		last = m.end
+	}
+	b.WriteString(base[last:])
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindPathParams(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		want []string
+	}{
+		{"no path", "http://localhost:8080", nil},
+		{"port is not a param", "http://localhost:8080/users", nil},
+		{"colon", "http://localhost:8080/users/:id", []string{"id"}},
+		{"braces", "http://x/users/{id}/posts/{post_id}", []string{"id", "post_id"}},
+		{"colon inside a segment", "http://x/files/a:b", nil},
+		{"template variable", "http://x/{{version}}/users/:id", []string{"id"}},
+		{"template variable host", "{{base}}/users/{id}", []string{"id"}},
+		{"unbalanced braces", "http://x/{{id}/{id}}", nil},
+		{"duplicates", "http://x/:id/copy/:id", []string{"id", "id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, m := range findPathParams(tt.base) {
+				names = append(names, m.name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("findPathParams(%q) = %q, want %q", tt.base, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathParamNames(t *testing.T) {
+	got := PathParamNames("http://x/:id/copy/:id/{other}?q=:not#:frag")
+	want := []string{"id", "other"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PathParamNames() = %q, want %q", got, want)
+	}
+}
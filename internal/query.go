@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// The query of a request is kept in its params, so that the URL and the
+// params cannot disagree. QueryURL puts them back together.
+
+// splitURL splits a URL into the part before the query, the raw query,
+// and the fragment including its "#"
+func splitURL(rawURL string) (base, query, fragment string) {
+	base, fragment, found := strings.Cut(rawURL, "#")
+	if found {
+		fragment = "#" + fragment
+	}
+	base, query, _ = strings.Cut(base, "?")
+	return base, query, fragment
+}
+
+// unescapeQuery decodes a key or a value of a query, or keeps it as it is
+// if it is not properly escaped
+func unescapeQuery(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// ParseQuery parses a raw query into enabled pairs, in order and with duplicates
+func ParseQuery(query string) KVPairs {
+	kvs := make(KVPairs, 0)
+	for _, part := range strings.Split(query, "&") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		kvs = kvs.Add(unescapeQuery(key), unescapeQuery(value))
+		kvs[len(kvs)-1].Bare = !found
+	}
+	return kvs
+}
+
+// templatePattern matches the template variables in a query, like `{{token}}`,
+// which read back the same when they are not escaped
+var templatePattern = regexp.MustCompile(`\{\{[^{}&=#+%]*\}\}`)
+
+// escapeQueryTemplate escapes a key or a value of a query,
+// but leaves its template variables as they are typed
+func escapeQueryTemplate(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range templatePattern.FindAllStringIndex(s, -1) {
+		b.WriteString(url.QueryEscape(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(url.QueryEscape(s[last:]))
+	return b.String()
+}
+
+// EncodeQuery percent-encodes the enabled pairs into a raw query, in order.
+// A bare pair with an empty value is written as its key only, e.g. "?flag",
+// and other pairs with an empty value as "key=".
+func EncodeQuery(kvs KVPairs) string {
+	return encodeQuery(kvs, url.QueryEscape)
+}
+
+func encodeQuery(kvs KVPairs, escape func(string) string) string {
+	parts := make([]string, 0, len(kvs))
+	for _, kv := range kvs.Enabled() {
+		part := escape(kv.Key)
+		if !kv.Bare || kv.Value != "" {
+			part += "=" + escape(kv.Value)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "&")
+}
+
+// SetURL sets the URL of the request, and moves its query to the params.
+// The query replaces the enabled params in order, and the disabled params
+// are kept at their place among them.
+func (r *Request) SetURL(rawURL string) {
+	base, query, fragment := splitURL(strings.TrimSpace(rawURL))
+	parsed := ParseQuery(query)
+	params := make(KVPairs, 0, len(parsed)+len(r.Params))
+	for _, kv := range r.Params {
+		if !kv.Enabled {
+			params = append(params, kv)
+		} else if len(parsed) > 0 {
+			params = append(params, parsed[0])
+			parsed = parsed[1:]
+		}
+	}
+	params = append(params, parsed...)
+	r.URL = base + fragment
+	r.Params = params
+	r.syncPathParams()
+}
+
+// QueryURL returns the URL with the enabled params in its query,
+// which is the URL shown and edited. Template variables in the query
+// are shown as they are typed.
+func (r Request) QueryURL() string {
+	return r.withQuery(r.URL, escapeQueryTemplate)
+}
+
+// ResolvedURL returns the URL with its path params substituted and
+// the enabled params in its query, which is the URL the request is sent to
+func (r Request) ResolvedURL() string {
+	base, query, fragment := splitURL(r.URL)
+	if query != "" {
+		query = "?" + query
+	}
+	return r.withQuery(r.expandPath(base)+query+fragment, url.QueryEscape)
+}
+
+func (r Request) withQuery(rawURL string, escape func(string) string) string {
+	params := encodeQuery(r.Params, escape)
+	if params == "" {
+		return rawURL
+	}
+	base, query, fragment := splitURL(rawURL)
+	if query != "" {
+		params = query + "&" + params
+	}
+	return base + "?" + params + fragment
+}
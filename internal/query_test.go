@@ -0,0 +1,247 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetURLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		stored string
+		params KVPairs
+	}{
+		{
+			name:   "no query",
+			url:    "http://localhost:8080/users",
+			stored: "http://localhost:8080/users",
+			params: KVPairs{},
+		},
+		{
+			name:   "pairs in order with duplicates",
+			url:    "http://localhost:8080/users?a=1&b=2&a=3",
+			stored: "http://localhost:8080/users",
+			params: KVPairs{
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "b", Value: "2", Enabled: true},
+				{Key: "a", Value: "3", Enabled: true},
+			},
+		},
+		{
+			name:   "empty value",
+			url:    "http://x/a?z=",
+			stored: "http://x/a",
+			params: KVPairs{{Key: "z", Value: "", Enabled: true}},
+		},
+		{
+			name:   "key without value",
+			url:    "http://x/a?flag&z=",
+			stored: "http://x/a",
+			params: KVPairs{
+				{Key: "flag", Value: "", Enabled: true, Bare: true},
+				{Key: "z", Value: "", Enabled: true},
+			},
+		},
+		{
+			name:   "plus is a space",
+			url:    "http://x/search?q=a+b",
+			stored: "http://x/search",
+			params: KVPairs{{Key: "q", Value: "a b", Enabled: true}},
+		},
+		{
+			name:   "escaped plus",
+			url:    "http://x/search?q=a%2Bb",
+			stored: "http://x/search",
+			params: KVPairs{{Key: "q", Value: "a+b", Enabled: true}},
+		},
+		{
+			name:   "fragment",
+			url:    "http://x/docs?page=2#section",
+			stored: "http://x/docs#section",
+			params: KVPairs{{Key: "page", Value: "2", Enabled: true}},
+		},
+		{
+			name:   "port and path params",
+			url:    "http://localhost:8080/users/:id?verbose=true",
+			stored: "http://localhost:8080/users/:id",
+			params: KVPairs{{Key: "verbose", Value: "true", Enabled: true}},
+		},
+		{
+			name:   "template variables",
+			url:    "{{base}}/users?token={{token}}",
+			stored: "{{base}}/users",
+			params: KVPairs{{Key: "token", Value: "{{token}}", Enabled: true}},
+		},
+		{
+			name:   "template variables among escaped text",
+			url:    "http://x/a?q=a+{{name}}%26b&{{key}}=1",
+			stored: "http://x/a",
+			params: KVPairs{
+				{Key: "q", Value: "a {{name}}&b", Enabled: true},
+				{Key: "{{key}}", Value: "1", Enabled: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Request
+			r.SetURL(tt.url)
+			if r.URL != tt.stored {
+				t.Errorf("URL = %q, want %q", r.URL, tt.stored)
+			}
+			if !reflect.DeepEqual(r.Params, tt.params) {
+				t.Errorf("Params = %+v, want %+v", r.Params, tt.params)
+			}
+			if got := r.QueryURL(); got != tt.url {
+				t.Errorf("QueryURL() = %q, want %q", got, tt.url)
+			}
+		})
+	}
+}
+
+func TestSetURLKeepsDisabledParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params KVPairs
+		url    string
+		want   KVPairs
+	}{
+		{
+			name: "replaced",
+			params: KVPairs{
+				{Key: "old", Value: "1", Enabled: true},
+				{Key: "debug", Value: "1", Enabled: false},
+			},
+			url: "http://x/a?new=2",
+			want: KVPairs{
+				{Key: "new", Value: "2", Enabled: true},
+				{Key: "debug", Value: "1", Enabled: false},
+			},
+		},
+		{
+			name: "in the middle",
+			params: KVPairs{
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "debug", Value: "1", Enabled: false},
+				{Key: "b", Value: "2", Enabled: true},
+			},
+			url: "http://x/a?a=1&b=3",
+			want: KVPairs{
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "debug", Value: "1", Enabled: false},
+				{Key: "b", Value: "3", Enabled: true},
+			},
+		},
+		{
+			name: "first, with params added",
+			params: KVPairs{
+				{Key: "debug", Value: "1", Enabled: false},
+				{Key: "a", Value: "1", Enabled: true},
+			},
+			url: "http://x/a?a=1&b=2&c=3",
+			want: KVPairs{
+				{Key: "debug", Value: "1", Enabled: false},
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "b", Value: "2", Enabled: true},
+				{Key: "c", Value: "3", Enabled: true},
+			},
+		},
+		{
+			name: "with params removed",
+			params: KVPairs{
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "b", Value: "2", Enabled: true},
+				{Key: "debug", Value: "1", Enabled: false},
+				{Key: "c", Value: "3", Enabled: true},
+			},
+			url: "http://x/a?a=1",
+			want: KVPairs{
+				{Key: "a", Value: "1", Enabled: true},
+				{Key: "debug", Value: "1", Enabled: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Request{Params: tt.params}
+			r.SetURL(tt.url)
+			if !reflect.DeepEqual(r.Params, tt.want) {
+				t.Errorf("Params = %+v, want %+v", r.Params, tt.want)
+			}
+			if got := r.QueryURL(); got != tt.url {
+				t.Errorf("QueryURL() = %q, want %q", got, tt.url)
+			}
+		})
+	}
+}
+
+func TestEncodeQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		kvs  KVPairs
+		want string
+	}{
+		{"empty", nil, ""},
+		{"added without value", KVPairs{}.Add("z", ""), "z="},
+		{"bare", KVPairs{{Key: "flag", Enabled: true, Bare: true}}, "flag"},
+		{"bare with value", KVPairs{{Key: "flag", Value: "1", Enabled: true, Bare: true}}, "flag=1"},
+		{"escaped", KVPairs{}.Add("a b", "c+d&e"), "a+b=c%2Bd%26e"},
+		{"disabled", KVPairs{{Key: "a", Value: "1"}, {Key: "b", Value: "2", Enabled: true}}, "b=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeQuery(tt.kvs); got != tt.want {
+				t.Errorf("EncodeQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvedURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		pathParams KVPairs
+		want       string
+	}{
+		{
+			name: "no path params",
+			url:  "http://localhost:8080/users?page=2#top",
+			want: "http://localhost:8080/users?page=2#top",
+		},
+		{
+			name:       "colon and brace params",
+			url:        "http://localhost:8080/users/:id/posts/{post}?z=",
+			pathParams: KVPairs{}.Add("id", "42").Add("post", "a b"),
+			want:       "http://localhost:8080/users/42/posts/a%20b?z=",
+		},
+		{
+			name:       "params without value are kept",
+			url:        "http://x/users/:id",
+			pathParams: KVPairs{}.Add("id", ""),
+			want:       "http://x/users/:id",
+		},
+		{
+			name:       "template variables are not path params",
+			url:        "{{base}}/users/{{id}}/:id",
+			pathParams: KVPairs{}.Add("id", "7").Add("base", "x"),
+			want:       "{{base}}/users/{{id}}/7",
+		},
+		{
+			name: "template variables in the query are escaped",
+			url:  "http://x/users?token={{token}}",
+			want: "http://x/users?token=%7B%7Btoken%7D%7D",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Request
+			r.SetURL(tt.url)
+			r.PathParams = tt.pathParams
+			if got := r.ResolvedURL(); got != tt.want {
+				t.Errorf("ResolvedURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
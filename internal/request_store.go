@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"fmt"
+	"hash/fnv"
 	"os"
 	"path/filepath"
 	"sync"
@@ -183,21 +185,7 @@ func (r *RequestFileStore) ListRequests() ([]Request, error) {
 	if err != nil {
 		return nil, err
 	}
-	// sort requests by catalog order
-	sortMap := make(map[string]int, len(catalog))
-	for i, id := range catalog {
-		sortMap[id] = i
-	}
-	sortedRequests := make([]Request, len(requests))
-	for _, id := range catalog {
-		for _, req := range requests {
-			if req.ID == id {
-				sortedRequests[sortMap[id]] = req
-				break
-			}
-		}
-	}
-	return sortedRequests, nil
+	return sortRequestsByCatalog(requests, catalog), nil
 }
 
 func (r *RequestFileStore) UpdateRequest(req Request) error {
@@ -212,3 +200,25 @@ func (r *RequestFileStore) DeleteRequest(id string) error {
 	}
 	return r.removeFromCatalog(id)
 }
+
+// ChangeToken summarizes the names, sizes and modification times
+// of the files in the store, without reading their content.
+func (r *RequestFileStore) ChangeToken() (string, error) {
+	return dirChangeToken(r.root)
+}
+
+func dirChangeToken(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New64a()
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s:%d:%d;", e.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return fmt.Sprintf("%x", h.Sum64()), nil
+}
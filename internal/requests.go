@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,11 +10,28 @@ import (
 	"sort"
 
 	"github.com/gabrielfu/agora/tui/styles"
+	"gopkg.in/yaml.v3"
 )
 
 type KVPair struct {
 	Key   string `json:"k" yaml:"key"`
 	Value string `json:"v" yaml:"value"`
+	// disabled params and headers are kept but not sent
+	Enabled bool `json:"e" yaml:"enabled"`
+	// a pair written without its separator, such as the "flag" of "?flag",
+	// is written back the same way while its value is empty
+	Bare bool `json:"b,omitempty" yaml:"bare,omitempty"`
+}
+
+// UnmarshalYAML enables the pairs saved before they could be disabled
+func (kv *KVPair) UnmarshalYAML(node *yaml.Node) error {
+	type plain KVPair
+	p := plain{Enabled: true}
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*kv = KVPair(p)
+	return nil
 }
 
 type KVPairs []KVPair
@@ -28,7 +46,18 @@ func (kvs KVPairs) Sort() {
 }
 
 func (kvs KVPairs) Add(key, value string) KVPairs {
-	return append(kvs, KVPair{Key: key, Value: value})
+	return append(kvs, KVPair{Key: key, Value: value, Enabled: true})
+}
+
+// Enabled returns the pairs that are enabled
+func (kvs KVPairs) Enabled() KVPairs {
+	var enabled KVPairs
+	for _, kv := range kvs {
+		if kv.Enabled {
+			enabled = append(enabled, kv)
+		}
+	}
+	return enabled
 }
 
 // Remove removes the first occurrence of the key-value pair from the list.
@@ -55,27 +84,35 @@ type Request struct {
 	Params  KVPairs `yaml:"params"`
 	Headers KVPairs `yaml:"headers"`
 	Auth    string  `yaml:"auth"`
+	// values of the `:name` and `{name}` segments of the URL
+	PathParams KVPairs `yaml:"path_params,omitempty"`
+	// jq filter of the JSON responses, last applied in the response pane
+	ResponseFilter string `yaml:"response_filter,omitempty"`
 }
 
 // NewRequest creates a new request with a random id.
 func NewRequest(method, url string) *Request {
-	return &Request{
+	r := &Request{
 		ID:     RandomID(),
 		Method: method,
-		URL:    url,
 	}
+	r.SetURL(url)
+	return r
 }
 
+// Copy returns a deep copy of the request.
 func (r Request) Copy() Request {
 	return Request{
-		ID:      r.ID,
-		Name:    r.Name,
-		Method:  r.Method,
-		URL:     r.URL,
-		Body:    r.Body,
-		Params:  r.Params,
-		Headers: r.Headers,
-		Auth:    r.Auth,
+		ID:             r.ID,
+		Name:           r.Name,
+		Method:         r.Method,
+		URL:            r.URL,
+		Body:           append([]byte(nil), r.Body...),
+		Params:         append(KVPairs(nil), r.Params...),
+		Headers:        append(KVPairs(nil), r.Headers...),
+		Auth:           r.Auth,
+		PathParams:     append(KVPairs(nil), r.PathParams...),
+		ResponseFilter: r.ResponseFilter,
 	}
 }
 
@@ -97,7 +134,7 @@ func (r *Request) WithBody(body []byte) *Request {
 }
 
 func (r *Request) WithParam(key, value string) *Request {
-	r.Params = append(r.Params, KVPair{Key: key, Value: value})
+	r.Params = r.Params.Add(key, value)
 	return r
 }
 
@@ -107,7 +144,7 @@ func (r *Request) WithParams(params KVPairs) *Request {
 }
 
 func (r *Request) WithHeader(key, value string) *Request {
-	r.Headers = append(r.Headers, KVPair{Key: key, Value: value})
+	r.Headers = r.Headers.Add(key, value)
 	return r
 }
 
@@ -123,8 +160,8 @@ func (r *Request) WithAuth(auth string) *Request {
 
 func (r Request) String() string {
 	return fmt.Sprintf(
-		"Request(ID=%s, Name=%s, Method=%s, URL=%s, Body=%v, Params=%v, Headers=%v, Auth=%s}",
-		r.ID, r.Name, r.Method, r.URL, r.Body, r.Params, r.Headers, r.Auth,
+		"Request(ID=%s, Name=%s, Method=%s, URL=%s, Body=%v, Params=%v, Headers=%v, Auth=%s, PathParams=%v}",
+		r.ID, r.Name, r.Method, r.URL, r.Body, r.Params, r.Headers, r.Auth, r.PathParams,
 	)
 }
 
@@ -141,6 +178,11 @@ func (r *Request) UpdateParam(index int, key, value string) {
 	r.Params[index].Value = value
 }
 
+// ToggleParam enables or disables a param
+func (r *Request) ToggleParam(index int) {
+	r.Params[index].Enabled = !r.Params[index].Enabled
+}
+
 func (r *Request) RemoveHeaderI(index int) {
 	r.Headers = r.Headers[:index+copy(r.Headers[index:], r.Headers[index+1:])]
 }
@@ -154,6 +196,11 @@ func (r *Request) UpdateHeader(index int, key, value string) {
 	r.Headers[index].Value = value
 }
 
+// ToggleHeader enables or disables a header
+func (r *Request) ToggleHeader(index int) {
+	r.Headers[index].Enabled = !r.Headers[index].Enabled
+}
+
 func makeJsonBodyReader(body []byte) (io.Reader, error) {
 	// todo: support other body dtype and content type
 	body = styles.MinifyJsonBytes(body)
@@ -168,23 +215,23 @@ func makeJsonBodyReader(body []byte) (io.Reader, error) {
 // If error is not nil, the request is considered failed.
 // Non-2xx status codes does not cause an error.
 func (r *Request) Exec() (*http.Response, error) {
+	return r.ExecContext(context.Background())
+}
+
+// ExecContext sends the request like Exec. The request is aborted,
+// along with the reading of the response body, when ctx is cancelled.
+func (r *Request) ExecContext(ctx context.Context) (*http.Response, error) {
 	body, err := makeJsonBodyReader(r.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(r.Method, r.URL, body)
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.ResolvedURL(), body)
 	if err != nil {
 		return nil, err
 	}
 
-	q := req.URL.Query()
-	for _, kv := range r.Params {
-		q.Add(kv.Key, kv.Value)
-	}
-	req.URL.RawQuery = q.Encode()
-
-	for _, kv := range r.Headers {
+	for _, kv := range r.Headers.Enabled() {
 		req.Header.Add(kv.Key, kv.Value)
 	}
 	client := &http.Client{}
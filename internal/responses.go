@@ -1,10 +1,16 @@
 package internal
 
+import "strings"
+
 type Response struct {
 	id         string
 	StatusCode int
 	Content    []byte
 	Headers    KVPairs
+	// Size is the size of the body, which is larger than Content if the body is truncated
+	Size int64
+	// BodyFile holds a large body, of which Content is a preview
+	BodyFile string
 }
 
 func NewResponse(statusCode int, content []byte, headers KVPairs) *Response {
@@ -13,6 +19,7 @@ func NewResponse(statusCode int, content []byte, headers KVPairs) *Response {
 		StatusCode: statusCode,
 		Content:    content,
 		Headers:    headers,
+		Size:       int64(len(content)),
 	}
 }
 
@@ -24,9 +31,10 @@ func (r Response) ID() string {
 	return r.id
 }
 
+// ContentType returns the Content-Type header. Header names are case-insensitive.
 func (r Response) ContentType() string {
 	for _, kv := range r.Headers {
-		if kv.Key == "Content-Type" {
+		if strings.EqualFold(kv.Key, "Content-Type") {
 			return kv.Value
 		}
 	}
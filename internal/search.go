@@ -0,0 +1,49 @@
+package internal
+
+import "github.com/sahilm/fuzzy"
+
+// FuzzyMatch is a target matched by a fuzzy search
+type FuzzyMatch struct {
+	// Index of the target
+	Index int
+	// Byte offsets of the matched characters in the target
+	MatchedIndexes []int
+}
+
+// FuzzyFind returns the targets matching query, best match first.
+// An empty query matches every target in its original order.
+func FuzzyFind(query string, targets []string) []FuzzyMatch {
+	if query == "" {
+		matches := make([]FuzzyMatch, len(targets))
+		for i := range targets {
+			matches[i] = FuzzyMatch{Index: i}
+		}
+		return matches
+	}
+	found := fuzzy.Find(query, targets)
+	matches := make([]FuzzyMatch, len(found))
+	for i, m := range found {
+		matches[i] = FuzzyMatch{Index: m.Index, MatchedIndexes: m.MatchedIndexes}
+	}
+	return matches
+}
+
+// SearchText is the text of a request matched by searches,
+// made of its method, name and URL.
+func (r Request) SearchText() string {
+	return r.Method + " " + r.Name + " " + r.URL
+}
+
+// SearchRequests returns the requests fuzzy matching query, best match first.
+func SearchRequests(requests []Request, query string) []Request {
+	targets := make([]string, len(requests))
+	for i, req := range requests {
+		targets[i] = req.SearchText()
+	}
+	matches := FuzzyFind(query, targets)
+	found := make([]Request, len(matches))
+	for i, m := range matches {
+		found[i] = requests[m.Index]
+	}
+	return found
+}
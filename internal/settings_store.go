@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The settings of a workspace are saved as a YAML map in `<root>/settings.yaml`.
+
+func (c *CollectionStore) calcSettingsFilename() string {
+	return filepath.Join(c.Root(), "settings.yaml")
+}
+
+func (c *CollectionStore) readSettings() (map[string]string, error) {
+	settings := make(map[string]string)
+	data, err := os.ReadFile(c.calcSettingsFilename())
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (c *CollectionStore) GetSetting(key string) (string, error) {
+	settings, err := c.readSettings()
+	if err != nil {
+		return "", err
+	}
+	return settings[key], nil
+}
+
+func (c *CollectionStore) SetSetting(key, value string) error {
+	settings, err := c.readSettings()
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(settings, key)
+	} else {
+		settings[key] = value
+	}
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.calcSettingsFilename(), data, 0644)
+}
@@ -0,0 +1,431 @@
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite"
+)
+
+// SQLite store of a workspace.
+// The whole workspace is saved in a single `<root>/agora.db` file.
+// Requests are saved as YAML documents, same as the file store,
+// so that workspace migrations can treat both backends alike.
+type SQLiteStorage struct {
+	root              string
+	db                *sql.DB
+	currentCollection string
+}
+
+const sqliteFilename = "agora.db"
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS collections (
+	name     TEXT PRIMARY KEY,
+	position INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS requests (
+	collection TEXT NOT NULL,
+	id         TEXT NOT NULL,
+	position   INTEGER NOT NULL,
+	data       BLOB NOT NULL,
+	PRIMARY KEY (collection, id)
+);
+CREATE TABLE IF NOT EXISTS trash (
+	id         TEXT PRIMARY KEY,
+	deleted_at INTEGER NOT NULL,
+	data       BLOB NOT NULL
+);
+CREATE TABLE IF NOT EXISTS settings (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+func sqlitePath(root string) string {
+	return filepath.Join(root, sqliteFilename)
+}
+
+func openSQLite(root string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", sqlitePath(root)+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func NewSQLiteStorage(root string) (*SQLiteStorage, error) {
+	db, err := openSQLite(root)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %w", err)
+	}
+	s := &SQLiteStorage{root: root, db: db}
+	if err := initCurrentCollection(s); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStorage) Root() string {
+	return s.root
+}
+
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// ChangeToken returns the data version of the database,
+// which changes when another connection commits a change.
+func (s *SQLiteStorage) ChangeToken() (string, error) {
+	return sqliteDataVersion(s.db)
+}
+
+func sqliteDataVersion(db *sql.DB) (string, error) {
+	var version int64
+	if err := db.QueryRow("PRAGMA data_version").Scan(&version); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(version, 10), nil
+}
+
+func (s *SQLiteStorage) ListCollections() ([]string, error) {
+	rows, err := s.db.Query("SELECT name FROM collections ORDER BY position, name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var collections []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		collections = append(collections, name)
+	}
+	return collections, rows.Err()
+}
+
+func (s *SQLiteStorage) GetFirstCollection() (string, error) {
+	collections, err := s.ListCollections()
+	if err != nil {
+		return "", err
+	}
+	if len(collections) == 0 {
+		return "", fmt.Errorf("no collections found")
+	}
+	return collections[0], nil
+}
+
+func (s *SQLiteStorage) CollectionExists(collection string) bool {
+	var n int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM collections WHERE name = ?", collection).Scan(&n)
+	return err == nil && n > 0
+}
+
+func (s *SQLiteStorage) CreateCollection(collection string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO collections (name, position)
+		VALUES (?, (SELECT COALESCE(MAX(position) + 1, 0) FROM collections))
+		ON CONFLICT (name) DO NOTHING`,
+		collection,
+	)
+	return err
+}
+
+func (s *SQLiteStorage) DeleteCollection(collection string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM requests WHERE collection = ?", collection); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM collections WHERE name = ?", collection); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RenameCollection(oldName, newName string) error {
+	if s.CollectionExists(newName) {
+		return fmt.Errorf("collection already exists: %s", newName)
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("UPDATE collections SET name = ? WHERE name = ?", newName, oldName); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE requests SET collection = ? WHERE collection = ?", newName, oldName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) DuplicateCollection(src, dst string) error {
+	return duplicateCollection(s, src, dst)
+}
+
+func (s *SQLiteStorage) WriteCollectionCatalog(catalog []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i, name := range catalog {
+		if _, err := tx.Exec("UPDATE collections SET position = ? WHERE name = ?", i, name); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) CurrentCollection() string {
+	return s.currentCollection
+}
+
+func (s *SQLiteStorage) SetCurrentCollection(collection string) {
+	s.currentCollection = collection
+}
+
+func (s *SQLiteStorage) Requests(collection string) (RequestStorage, error) {
+	if !s.CollectionExists(collection) {
+		return nil, fmt.Errorf("collection not found: %s", collection)
+	}
+	return &SQLiteRequestStore{db: s.db, collection: collection}, nil
+}
+
+func (s *SQLiteStorage) GetSetting(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+func (s *SQLiteStorage) SetSetting(key, value string) error {
+	if value == "" {
+		_, err := s.db.Exec("DELETE FROM settings WHERE key = ?", key)
+		return err
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		key, value,
+	)
+	return err
+}
+
+func (s *SQLiteStorage) AddToTrash(item TrashItem) error {
+	data, err := yaml.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(
+		"INSERT INTO trash (id, deleted_at, data) VALUES (?, ?, ?)",
+		item.ID, item.DeletedAt.UnixNano(), data,
+	)
+	return err
+}
+
+func (s *SQLiteStorage) ListTrash() ([]TrashItem, error) {
+	rows, err := s.db.Query("SELECT data FROM trash ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrashItem
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var item TrashItem
+		if err := yaml.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (s *SQLiteStorage) RemoveFromTrash(id string) error {
+	_, err := s.db.Exec("DELETE FROM trash WHERE id = ?", id)
+	return err
+}
+
+// SQLite store of a single collection
+type SQLiteRequestStore struct {
+	db         *sql.DB
+	collection string
+}
+
+func (r *SQLiteRequestStore) ChangeToken() (string, error) {
+	return sqliteDataVersion(r.db)
+}
+
+func (r *SQLiteRequestStore) CreateRequest(req Request) error {
+	data, err := yaml.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT INTO requests (collection, id, position, data)
+		VALUES (?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM requests WHERE collection = ?), ?)
+		ON CONFLICT (collection, id) DO UPDATE SET data = excluded.data`,
+		r.collection, req.ID, r.collection, data,
+	)
+	return err
+}
+
+func (r *SQLiteRequestStore) GetRequest(id string) (Request, error) {
+	var data []byte
+	err := r.db.QueryRow(
+		"SELECT data FROM requests WHERE collection = ? AND id = ?",
+		r.collection, id,
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return Request{}, fmt.Errorf("request not found: %s", id)
+	}
+	if err != nil {
+		return Request{}, err
+	}
+	var req Request
+	err = yaml.Unmarshal(data, &req)
+	return req, err
+}
+
+func (r *SQLiteRequestStore) ListRequests() ([]Request, error) {
+	rows, err := r.db.Query(
+		"SELECT data FROM requests WHERE collection = ? ORDER BY position",
+		r.collection,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var requests []Request
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var req Request
+		if err := yaml.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
+}
+
+func (r *SQLiteRequestStore) UpdateRequest(req Request) error {
+	return r.CreateRequest(req)
+}
+
+func (r *SQLiteRequestStore) DeleteRequest(id string) error {
+	_, err := r.db.Exec("DELETE FROM requests WHERE collection = ? AND id = ?", r.collection, id)
+	return err
+}
+
+func (r *SQLiteRequestStore) ReadCatalog() ([]string, error) {
+	rows, err := r.db.Query(
+		"SELECT id FROM requests WHERE collection = ? ORDER BY position",
+		r.collection,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var catalog []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		catalog = append(catalog, id)
+	}
+	return catalog, rows.Err()
+}
+
+func (r *SQLiteRequestStore) WriteCatalog(catalog []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i, id := range catalog {
+		_, err := tx.Exec(
+			"UPDATE requests SET position = ? WHERE collection = ? AND id = ?",
+			i, r.collection, id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// rewriteSQLiteRequests updates every request, for workspace migrations
+func rewriteSQLiteRequests(db *sql.DB, update func(*Request)) error {
+	type row struct {
+		collection string
+		id         string
+		data       []byte
+	}
+	rows, err := db.Query("SELECT collection, id, data FROM requests")
+	if err != nil {
+		return err
+	}
+	var requests []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.collection, &r.id, &r.data); err != nil {
+			rows.Close()
+			return err
+		}
+		requests = append(requests, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range requests {
+		var req Request
+		if err := yaml.Unmarshal(r.data, &req); err != nil {
+			return err
+		}
+		update(&req)
+		data, err := yaml.Marshal(req)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			"UPDATE requests SET data = ? WHERE collection = ? AND id = ?",
+			data, r.collection, r.id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
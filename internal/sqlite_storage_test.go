@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// storageBackends opens an empty workspace with each storage backend
+var storageBackends = []struct {
+	name string
+	open func(t *testing.T) Storage
+}{
+	{FileStorageBackend, func(t *testing.T) Storage {
+		s, err := NewCollectionStore(filepath.Join(t.TempDir(), "workspace"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}},
+	{SQLiteStorageBackend, func(t *testing.T) Storage {
+		s, err := NewSQLiteStorage(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	}},
+	{MemoryStorageBackend, func(t *testing.T) Storage {
+		return NewMemoryStorage()
+	}},
+}
+
+func requestIDs(t *testing.T, store RequestStorage) []string {
+	t.Helper()
+	requests, err := store.ListRequests()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, req := range requests {
+		ids = append(ids, req.ID)
+	}
+	return ids
+}
+
+func TestStorageRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit is applied to a collection holding the requests a, b and c
+		edit func(store RequestStorage) error
+		want []string
+	}{
+		{
+			name: "create",
+			edit: func(store RequestStorage) error {
+				return store.CreateRequest(Request{ID: "d", Method: "GET"})
+			},
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "update keeps the position",
+			edit: func(store RequestStorage) error {
+				return store.UpdateRequest(Request{ID: "b", Method: "POST", URL: "http://x/b"})
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "reorder",
+			edit: func(store RequestStorage) error {
+				catalog, err := store.ReadCatalog()
+				if err != nil {
+					return err
+				}
+				return store.WriteCatalog(ReorderCatalog(catalog, "c", 0))
+			},
+			want: []string{"c", "a", "b"},
+		},
+		{
+			name: "delete",
+			edit: func(store RequestStorage) error {
+				return store.DeleteRequest("b")
+			},
+			want: []string{"a", "c"},
+		},
+	}
+	for _, backend := range storageBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				s := backend.open(t)
+				store, err := s.Requests(s.CurrentCollection())
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, id := range []string{"a", "b", "c"} {
+					if err := store.CreateRequest(Request{ID: id, Method: "GET"}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := tt.edit(store); err != nil {
+					t.Fatal(err)
+				}
+				if got := requestIDs(t, store); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("requests = %q, want %q", got, tt.want)
+				}
+				if catalog, err := store.ReadCatalog(); err != nil || !reflect.DeepEqual(catalog, tt.want) {
+					t.Errorf("ReadCatalog() = %q, %v, want %q", catalog, err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestStorageUpdateRequest(t *testing.T) {
+	for _, backend := range storageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := backend.open(t)
+			store, err := s.Requests(s.CurrentCollection())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.CreateRequest(Request{ID: "a", Method: "GET"}); err != nil {
+				t.Fatal(err)
+			}
+			want := Request{
+				ID:      "a",
+				Method:  "POST",
+				URL:     "http://x/users",
+				Params:  KVPairs{{Key: "page", Value: "2", Enabled: true}},
+				Headers: KVPairs{{Key: "Accept", Value: "*/*"}},
+			}
+			if err := store.UpdateRequest(want); err != nil {
+				t.Fatal(err)
+			}
+			got, err := store.GetRequest("a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Method != want.Method || got.URL != want.URL ||
+				!reflect.DeepEqual(got.Params, want.Params) || !reflect.DeepEqual(got.Headers, want.Headers) {
+				t.Errorf("GetRequest() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestStorageCollections(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit is applied to a workspace holding the collections a and b
+		edit func(s Storage) error
+		want []string
+	}{
+		{
+			name: "create",
+			edit: func(s Storage) error { return s.CreateCollection("c") },
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "rename keeps the position",
+			edit: func(s Storage) error { return s.RenameCollection("a", "z") },
+			want: []string{"z", "b"},
+		},
+		{
+			name: "reorder",
+			edit: func(s Storage) error { return s.WriteCollectionCatalog([]string{"b", "a"}) },
+			want: []string{"b", "a"},
+		},
+		{
+			name: "delete",
+			edit: func(s Storage) error { return s.DeleteCollection("a") },
+			want: []string{"b"},
+		},
+		{
+			name: "duplicate",
+			edit: func(s Storage) error { return s.DuplicateCollection("a", "c") },
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, backend := range storageBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				s := backend.open(t)
+				if err := s.RenameCollection(s.CurrentCollection(), "a"); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.CreateCollection("b"); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.WriteCollectionCatalog([]string{"a", "b"}); err != nil {
+					t.Fatal(err)
+				}
+				if err := tt.edit(s); err != nil {
+					t.Fatal(err)
+				}
+				got, err := s.ListCollections()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ListCollections() = %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestStorageDuplicateCollection(t *testing.T) {
+	for _, backend := range storageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := backend.open(t)
+			src := s.CurrentCollection()
+			store, err := s.Requests(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range []string{"b", "a"} {
+				if err := store.CreateRequest(Request{ID: id, Method: "GET", URL: "http://x/" + id}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.DuplicateCollection(src, "copy"); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.DuplicateCollection(src, "copy"); err == nil {
+				t.Error("duplicating into an existing collection succeeded")
+			}
+			copies, err := s.Requests("copy")
+			if err != nil {
+				t.Fatal(err)
+			}
+			requests, err := copies.ListRequests()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var urls []string
+			for _, req := range requests {
+				if req.ID == "a" || req.ID == "b" {
+					t.Errorf("copy of %s kept its ID", req.ID)
+				}
+				urls = append(urls, req.URL)
+			}
+			if want := []string{"http://x/b", "http://x/a"}; !reflect.DeepEqual(urls, want) {
+				t.Errorf("copied URLs = %q, want %q", urls, want)
+			}
+		})
+	}
+}
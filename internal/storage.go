@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Storage backends of a workspace
+const (
+	FileStorageBackend   = "file"
+	SQLiteStorageBackend = "sqlite"
+	MemoryStorageBackend = "memory"
+)
+
+// CollectionStorage manages the collections of a workspace
+// and keeps track of the collection currently in use.
+type CollectionStorage interface {
+	ListCollections() ([]string, error)
+	GetFirstCollection() (string, error)
+	CollectionExists(collection string) bool
+	CreateCollection(collection string) error
+	DeleteCollection(collection string) error
+	RenameCollection(oldName, newName string) error
+	// DuplicateCollection copies all requests of src into a new collection dst.
+	DuplicateCollection(src, dst string) error
+	// WriteCollectionCatalog sets the display order of the collections.
+	WriteCollectionCatalog(catalog []string) error
+	CurrentCollection() string
+	SetCurrentCollection(collection string)
+	// Requests returns the request storage of a collection.
+	Requests(collection string) (RequestStorage, error)
+}
+
+// RequestStorage manages the requests of a single collection.
+type RequestStorage interface {
+	CreateRequest(req Request) error
+	GetRequest(id string) (Request, error)
+	// ListRequests returns all requests sorted by the catalog order.
+	ListRequests() ([]Request, error)
+	UpdateRequest(req Request) error
+	DeleteRequest(id string) error
+	// Catalog is the list of request IDs in display order
+	ReadCatalog() ([]string, error)
+	WriteCatalog(catalog []string) error
+}
+
+// SettingsStorage keeps the settings of a workspace as key-value pairs.
+type SettingsStorage interface {
+	// GetSetting returns the value of a setting, or "" if it is not set.
+	GetSetting(key string) (string, error)
+	// SetSetting sets the value of a setting. An empty value unsets it.
+	SetSetting(key, value string) error
+}
+
+// Storage is a workspace storage backend.
+type Storage interface {
+	CollectionStorage
+	TrashStorage
+	SettingsStorage
+	Close() error
+}
+
+// sortRequestsByCatalog sorts requests by the order of the catalog.
+// Requests not in the catalog are dropped.
+func sortRequestsByCatalog(requests []Request, catalog []string) []Request {
+	byID := make(map[string]Request, len(requests))
+	for _, req := range requests {
+		byID[req.ID] = req
+	}
+	sorted := make([]Request, 0, len(catalog))
+	for _, id := range catalog {
+		if req, ok := byID[id]; ok {
+			sorted = append(sorted, req)
+		}
+	}
+	return sorted
+}
+
+// sortByCatalog sorts names by the order of the catalog.
+// Names not in the catalog are kept at the end in their original order.
+func sortByCatalog(names []string, catalog []string) []string {
+	index := make(map[string]int, len(catalog))
+	for i, name := range catalog {
+		index[name] = i
+	}
+	sorted := append([]string(nil), names...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, aok := index[sorted[i]]
+		b, bok := index[sorted[j]]
+		if aok && bok {
+			return a < b
+		}
+		return aok && !bok
+	})
+	return sorted
+}
+
+// ReorderCatalog moves id to the given index of the catalog.
+func ReorderCatalog(catalog []string, id string, index int) []string {
+	var reordered []string
+	for _, v := range catalog {
+		if v != id {
+			reordered = append(reordered, v)
+		}
+	}
+	if len(reordered) == len(catalog) {
+		return catalog
+	}
+	index = max(0, min(index, len(reordered)))
+	reordered = append(reordered[:index], append([]string{id}, reordered[index:]...)...)
+	return reordered
+}
+
+// duplicateCollection copies the requests of src into a new collection dst,
+// preserving their order. The copies are given new IDs.
+func duplicateCollection(c CollectionStorage, src, dst string) error {
+	if c.CollectionExists(dst) {
+		return fmt.Errorf("collection already exists: %s", dst)
+	}
+	srcStore, err := c.Requests(src)
+	if err != nil {
+		return err
+	}
+	requests, err := srcStore.ListRequests()
+	if err != nil {
+		return err
+	}
+	if err := c.CreateCollection(dst); err != nil {
+		return err
+	}
+	dstStore, err := c.Requests(dst)
+	if err != nil {
+		return err
+	}
+	for _, req := range requests {
+		if err := dstStore.CreateRequest(req.CopyWithNewID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initCurrentCollection selects the first collection as the current one,
+// creating the default collection if the storage has none.
+func initCurrentCollection(c CollectionStorage) error {
+	collections, err := c.ListCollections()
+	if err != nil {
+		return err
+	}
+	if len(collections) > 0 {
+		c.SetCurrentCollection(collections[0])
+		return nil
+	}
+	if err := c.CreateCollection(DEFAULT_COLLECTION_NAME); err != nil {
+		return err
+	}
+	c.SetCurrentCollection(DEFAULT_COLLECTION_NAME)
+	return nil
+}
+
+// OpenStorage migrates the workspace at root and opens its storage backend.
+// The backend recorded in the workspace manifest takes precedence;
+// backend is only used to initialize a new workspace and may be empty.
+func OpenStorage(root, backend string) (Storage, error) {
+	if backend == MemoryStorageBackend {
+		return NewMemoryStorage(), nil
+	}
+	if err := MigrateWorkspace(root); err != nil {
+		return nil, err
+	}
+	manifest, _, err := ReadWorkspaceManifest(root)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Storage == "" {
+		manifest.Storage = FileStorageBackend
+		if backend != "" && isEmptyWorkspace(root) {
+			manifest.Storage = backend
+		}
+		if err := WriteWorkspaceManifest(root, manifest); err != nil {
+			return nil, err
+		}
+	}
+	if backend != "" && backend != manifest.Storage {
+		return nil, fmt.Errorf("workspace %s uses %s storage, not %s", root, manifest.Storage, backend)
+	}
+
+	switch manifest.Storage {
+	case FileStorageBackend:
+		return NewCollectionStore(root)
+	case SQLiteStorageBackend:
+		return NewSQLiteStorage(root)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", manifest.Storage)
+	}
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// Deleted requests and collections are moved to the trash of the workspace,
+// from where they can be restored.
+
+const (
+	TrashItemRequest    = "request"
+	TrashItemCollection = "collection"
+)
+
+type TrashItem struct {
+	ID         string    `yaml:"id"`
+	Kind       string    `yaml:"kind"`
+	Collection string    `yaml:"collection"`
+	Requests   []Request `yaml:"requests"`
+	// position of a deleted request in its collection
+	Position  int       `yaml:"position"`
+	DeletedAt time.Time `yaml:"deleted_at"`
+}
+
+func (t TrashItem) String() string {
+	switch t.Kind {
+	case TrashItemCollection:
+		return fmt.Sprintf("collection %s (%d requests)", t.Collection, len(t.Requests))
+	default:
+		name := "untitled"
+		if len(t.Requests) > 0 {
+			req := t.Requests[0]
+			if req.Name != "" {
+				name = req.Name
+			} else if req.URL != "" {
+				name = req.URL
+			}
+		}
+		return fmt.Sprintf("%s / %s", t.Collection, name)
+	}
+}
+
+// TrashRetention is how long deleted items are kept in the trash.
+const TrashRetention = 30 * 24 * time.Hour
+
+// TrashStorage manages the trash of a workspace.
+type TrashStorage interface {
+	AddToTrash(item TrashItem) error
+	// ListTrash returns the items in the trash, most recently deleted first.
+	ListTrash() ([]TrashItem, error)
+	RemoveFromTrash(id string) error
+}
+
+// TrashRequest moves a request of the collection to the trash
+// and returns the ID of the trash item.
+func TrashRequest(s Storage, collection, id string) (string, error) {
+	store, err := s.Requests(collection)
+	if err != nil {
+		return "", err
+	}
+	req, err := store.GetRequest(id)
+	if err != nil {
+		return "", err
+	}
+	catalog, err := store.ReadCatalog()
+	if err != nil {
+		return "", err
+	}
+	item := TrashItem{
+		ID:         RandomID(),
+		Kind:       TrashItemRequest,
+		Collection: collection,
+		Requests:   []Request{req},
+		Position:   len(catalog),
+		DeletedAt:  time.Now(),
+	}
+	for i, v := range catalog {
+		if v == id {
+			item.Position = i
+		}
+	}
+	if err := s.AddToTrash(item); err != nil {
+		return "", err
+	}
+	if err := store.DeleteRequest(id); err != nil {
+		s.RemoveFromTrash(item.ID)
+		return "", err
+	}
+	return item.ID, nil
+}
+
+// TrashCollection moves a collection and all its requests to the trash
+// and returns the ID of the trash item.
+func TrashCollection(s Storage, collection string) (string, error) {
+	store, err := s.Requests(collection)
+	if err != nil {
+		return "", err
+	}
+	requests, err := store.ListRequests()
+	if err != nil {
+		return "", err
+	}
+	item := TrashItem{
+		ID:         RandomID(),
+		Kind:       TrashItemCollection,
+		Collection: collection,
+		Requests:   requests,
+		DeletedAt:  time.Now(),
+	}
+	if err := s.AddToTrash(item); err != nil {
+		return "", err
+	}
+	if err := s.DeleteCollection(collection); err != nil {
+		s.RemoveFromTrash(item.ID)
+		return "", err
+	}
+	return item.ID, nil
+}
+
+// RestoreFromTrash restores a trash item and removes it from the trash.
+// A request is restored into its collection, which is recreated if needed.
+// A collection is restored under a new name if the name has been taken.
+func RestoreFromTrash(s Storage, id string) error {
+	items, err := s.ListTrash()
+	if err != nil {
+		return err
+	}
+	var item *TrashItem
+	for i := range items {
+		if items[i].ID == id {
+			item = &items[i]
+			break
+		}
+	}
+	if item == nil {
+		return fmt.Errorf("trash item not found: %s", id)
+	}
+
+	collection := item.Collection
+	if item.Kind == TrashItemCollection {
+		for n := 2; s.CollectionExists(collection); n++ {
+			collection = fmt.Sprintf("%s (%d)", item.Collection, n)
+		}
+	}
+	if !s.CollectionExists(collection) {
+		if err := s.CreateCollection(collection); err != nil {
+			return err
+		}
+	}
+	store, err := s.Requests(collection)
+	if err != nil {
+		return err
+	}
+	for _, req := range item.Requests {
+		if _, err := store.GetRequest(req.ID); err == nil {
+			req = req.CopyWithNewID()
+		}
+		if err := store.CreateRequest(req); err != nil {
+			return err
+		}
+		if item.Kind == TrashItemRequest {
+			catalog, err := store.ReadCatalog()
+			if err != nil {
+				return err
+			}
+			if err := store.WriteCatalog(ReorderCatalog(catalog, req.ID, item.Position)); err != nil {
+				return err
+			}
+		}
+	}
+	return s.RemoveFromTrash(id)
+}
+
+// ExpireTrash removes the items that were deleted before the given time
+// from the trash, so that it does not grow forever.
+func ExpireTrash(s TrashStorage, before time.Time) error {
+	items, err := s.ListTrash()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.DeletedAt.Before(before) {
+			if err := s.RemoveFromTrash(item.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
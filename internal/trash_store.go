@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Each trash item is saved as a YAML file under `<root>/trash`.
+
+func (c *CollectionStore) trashDir() string {
+	return filepath.Join(c.Root(), "trash")
+}
+
+func (c *CollectionStore) calcTrashFilename(id string) string {
+	return filepath.Join(c.trashDir(), id)
+}
+
+func (c *CollectionStore) AddToTrash(item TrashItem) error {
+	if err := os.MkdirAll(c.trashDir(), 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	data, err := yaml.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.calcTrashFilename(item.ID), data, 0644)
+}
+
+func (c *CollectionStore) ListTrash() ([]TrashItem, error) {
+	entries, err := os.ReadDir(c.trashDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []TrashItem
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.trashDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var item TrashItem
+		if err := yaml.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+func (c *CollectionStore) RemoveFromTrash(id string) error {
+	return os.Remove(c.calcTrashFilename(id))
+}
@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRestoreRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit changes the workspace after the request b of the collection api
+		// holding a, b and c was trashed
+		edit func(s Storage) error
+		// the sorted collections and the requests of api after the restore
+		wantCollections []string
+		wantRequests    []string
+	}{
+		{
+			name:            "into its position",
+			edit:            func(s Storage) error { return nil },
+			wantCollections: []string{"api"},
+			wantRequests:    []string{"a", "b", "c"},
+		},
+		{
+			name: "after other requests were deleted",
+			edit: func(s Storage) error {
+				store, err := s.Requests("api")
+				if err != nil {
+					return err
+				}
+				return store.DeleteRequest("a")
+			},
+			wantCollections: []string{"api"},
+			wantRequests:    []string{"c", "b"},
+		},
+		{
+			name:            "into a renamed collection",
+			edit:            func(s Storage) error { return s.RenameCollection("api", "renamed") },
+			wantCollections: []string{"api", "renamed"},
+			wantRequests:    []string{"b"},
+		},
+		{
+			name:            "into a deleted collection",
+			edit:            func(s Storage) error { return s.DeleteCollection("api") },
+			wantCollections: []string{"api"},
+			wantRequests:    []string{"b"},
+		},
+	}
+	for _, backend := range storageBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				s := backend.open(t)
+				if err := s.RenameCollection(s.CurrentCollection(), "api"); err != nil {
+					t.Fatal(err)
+				}
+				store, err := s.Requests("api")
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, id := range []string{"a", "b", "c"} {
+					if err := store.CreateRequest(Request{ID: id, Method: "GET"}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				id, err := TrashRequest(s, "api", "b")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := requestIDs(t, store); !reflect.DeepEqual(got, []string{"a", "c"}) {
+					t.Fatalf("requests after trashing = %q", got)
+				}
+				if err := tt.edit(s); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := RestoreFromTrash(s, id); err != nil {
+					t.Fatal(err)
+				}
+				// the file backend lists collections without a catalog by name
+				got, _ := s.ListCollections()
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tt.wantCollections) {
+					t.Errorf("collections = %q, want %q", got, tt.wantCollections)
+				}
+				store, err = s.Requests("api")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := requestIDs(t, store); !reflect.DeepEqual(got, tt.wantRequests) {
+					t.Errorf("requests = %q, want %q", got, tt.wantRequests)
+				}
+				if items, err := s.ListTrash(); err != nil || len(items) != 0 {
+					t.Errorf("ListTrash() = %v, %v, want it empty", items, err)
+				}
+			})
+		}
+	}
+}
+
+func TestRestoreCollection(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit changes the workspace after the collection api was trashed
+		edit func(s Storage) error
+		// the collection the requests are restored into
+		want string
+	}{
+		{
+			name: "under its name",
+			edit: func(s Storage) error { return nil },
+			want: "api",
+		},
+		{
+			name: "under a new name if the name was taken",
+			edit: func(s Storage) error { return s.CreateCollection("api") },
+			want: "api (2)",
+		},
+	}
+	for _, backend := range storageBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				s := backend.open(t)
+				if err := s.CreateCollection("api"); err != nil {
+					t.Fatal(err)
+				}
+				store, err := s.Requests("api")
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, id := range []string{"b", "a"} {
+					if err := store.CreateRequest(Request{ID: id, Method: "GET"}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				id, err := TrashCollection(s, "api")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.CollectionExists("api") {
+					t.Fatal("trashed collection still exists")
+				}
+				if err := tt.edit(s); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := RestoreFromTrash(s, id); err != nil {
+					t.Fatal(err)
+				}
+				store, err = s.Requests(tt.want)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := requestIDs(t, store), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("requests of %s = %q, want %q", tt.want, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestExpireTrash(t *testing.T) {
+	now := time.Now()
+	for _, backend := range storageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := backend.open(t)
+			for id, age := range map[string]time.Duration{"old": 2 * TrashRetention, "new": time.Hour} {
+				item := TrashItem{ID: id, Kind: TrashItemCollection, Collection: id, DeletedAt: now.Add(-age)}
+				if err := s.AddToTrash(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := ExpireTrash(s, now.Add(-TrashRetention)); err != nil {
+				t.Fatal(err)
+			}
+			items, err := s.ListTrash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != 1 || items[0].ID != "new" {
+				t.Errorf("ListTrash() = %v, want only new", items)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFilename is the name of the manifest in the workspace root
+const manifestFilename = "workspace.yaml"
+
+// WorkspaceManifest records the schema version of the data
+// under the workspace root (e.g. ~/.agora), so that older
+// workspaces can be upgraded and newer ones are not corrupted
+// by an outdated binary.
+type WorkspaceManifest struct {
+	Version int `yaml:"version"`
+	// Storage is the backend of the workspace, e.g. file or sqlite
+	Storage string `yaml:"storage,omitempty"`
+}
+
+// migration upgrades the workspace at root by one schema version
+type migration struct {
+	description string
+	apply       func(root string) error
+}
+
+// migrations[i] upgrades a workspace from version i to version i+1.
+// Append new migrations to the end; never modify or reorder existing ones.
+var migrations = []migration{
+	{"create request catalog for every collection", migrateCreateCatalogs},
+	{"enable every param and header", migrateEnableKVPairs},
+	{"move the query of URLs to the params", migrateQueryToParams},
+}
+
+// CurrentSchemaVersion is the workspace schema version this binary writes.
+var CurrentSchemaVersion = len(migrations)
+
+// WorkspaceVersionError is returned when the workspace was written
+// by a newer version of agora than the running binary.
+type WorkspaceVersionError struct {
+	Root      string
+	Version   int
+	Supported int
+}
+
+func (e *WorkspaceVersionError) Error() string {
+	return fmt.Sprintf(
+		"workspace %s has schema version %d, but this version of agora only supports up to version %d. Please upgrade agora to open this workspace",
+		e.Root, e.Version, e.Supported,
+	)
+}
+
+func manifestPath(root string) string {
+	return filepath.Join(root, manifestFilename)
+}
+
+// ReadWorkspaceManifest reads the manifest of the workspace.
+// The returned bool is false if the manifest does not exist.
+func ReadWorkspaceManifest(root string) (WorkspaceManifest, bool, error) {
+	data, err := os.ReadFile(manifestPath(root))
+	if os.IsNotExist(err) {
+		return WorkspaceManifest{}, false, nil
+	}
+	if err != nil {
+		return WorkspaceManifest{}, false, err
+	}
+	var manifest WorkspaceManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return WorkspaceManifest{}, true, fmt.Errorf("invalid workspace manifest: %w", err)
+	}
+	return manifest, true, nil
+}
+
+func WriteWorkspaceManifest(root string, manifest WorkspaceManifest) error {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	return os.WriteFile(manifestPath(root), data, 0644)
+}
+
+// isEmptyWorkspace reports whether the workspace has no collections yet.
+func isEmptyWorkspace(root string) bool {
+	entries, err := os.ReadDir(filepath.Join(root, "collections"))
+	return err != nil || len(entries) == 0
+}
+
+// backupWorkspace copies the collections and the SQLite database of the
+// workspace into `<root>/backups/<timestamp>-v<version>` and returns the
+// backup directory.
+func backupWorkspace(root string, version int) (string, error) {
+	name := fmt.Sprintf("%s-v%d", time.Now().Format("20060102-150405"), version)
+	dst := filepath.Join(root, "backups", name)
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(root, "collections")); err == nil {
+		if err := copyDir(filepath.Join(root, "collections"), filepath.Join(dst, "collections")); err != nil {
+			return "", err
+		}
+	}
+	if _, err := os.Stat(sqlitePath(root)); err == nil {
+		if err := copyFile(sqlitePath(root), sqlitePath(dst)); err != nil {
+			return "", err
+		}
+	}
+	return dst, nil
+}
+
+// MigrateWorkspace upgrades the workspace at root to CurrentSchemaVersion.
+// Workspaces without a manifest are treated as version 0.
+// A backup is taken before any migration is applied.
+func MigrateWorkspace(root string) error {
+	manifest, exists, err := ReadWorkspaceManifest(root)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if isEmptyWorkspace(root) {
+			return WriteWorkspaceManifest(root, WorkspaceManifest{Version: CurrentSchemaVersion})
+		}
+		manifest.Version = 0
+	}
+	if manifest.Version > CurrentSchemaVersion {
+		return &WorkspaceVersionError{Root: root, Version: manifest.Version, Supported: CurrentSchemaVersion}
+	}
+	if manifest.Version == CurrentSchemaVersion {
+		return nil
+	}
+
+	backup, err := backupWorkspace(root, manifest.Version)
+	if err != nil {
+		return fmt.Errorf("error backing up workspace: %w", err)
+	}
+	for v := manifest.Version; v < CurrentSchemaVersion; v++ {
+		m := migrations[v]
+		if err := m.apply(root); err != nil {
+			return fmt.Errorf(
+				"error migrating workspace to version %d (%s): %w. A backup is available at %s",
+				v+1, m.description, err, backup,
+			)
+		}
+		manifest.Version = v + 1
+		if err := WriteWorkspaceManifest(root, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachCollectionRequestDir calls fn with the request directory of every collection.
+func forEachCollectionRequestDir(root string, fn func(dir string) error) error {
+	entries, err := os.ReadDir(filepath.Join(root, "collections"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, "collections", entry.Name(), "requests")
+		if err := fn(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// v0 -> v1: requests missing from the catalog of their collection are
+// not listed. Every request file is added to the catalog, after the
+// listed ones, and the IDs of the requests that no longer exist are dropped.
+func migrateCreateCatalogs(root string) error {
+	return forEachCollectionRequestDir(root, func(dir string) error {
+		store, err := NewRequestFileStore(dir)
+		if err != nil {
+			return err
+		}
+		catalog, err := store.ReadCatalog()
+		if err != nil {
+			return err
+		}
+		requests, err := store.listRequestsUnordered()
+		if err != nil {
+			return err
+		}
+		return store.WriteCatalog(reconcileCatalog(catalog, requests))
+	})
+}
+
+// reconcileCatalog returns the catalog with the requests in it, in order,
+// followed by the requests that are missing from it
+func reconcileCatalog(catalog []string, requests []Request) []string {
+	exists := make(map[string]bool, len(requests))
+	for _, req := range requests {
+		exists[req.ID] = true
+	}
+	listed := make(map[string]bool, len(catalog))
+	reconciled := make([]string, 0, len(requests))
+	for _, id := range catalog {
+		if exists[id] && !listed[id] {
+			listed[id] = true
+			reconciled = append(reconciled, id)
+		}
+	}
+	for _, req := range requests {
+		if !listed[req.ID] {
+			listed[req.ID] = true
+			reconciled = append(reconciled, req.ID)
+		}
+	}
+	return reconciled
+}
+
+// rewriteRequests updates every request of the workspace,
+// in both storage backends
+func rewriteRequests(root string, update func(*Request)) error {
+	err := forEachCollectionRequestDir(root, func(dir string) error {
+		store, err := NewRequestFileStore(dir)
+		if err != nil {
+			return err
+		}
+		requests, err := store.listRequestsUnordered()
+		if err != nil {
+			return err
+		}
+		for _, req := range requests {
+			update(&req)
+			if err := store.UpdateRequest(req); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(sqlitePath(root)); err != nil {
+		return nil
+	}
+	db, err := openSQLite(root)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return rewriteSQLiteRequests(db, update)
+}
+
+// v1 -> v2: params and headers can be disabled. Requests are rewritten
+// with every pair enabled, as they are read.
+func migrateEnableKVPairs(root string) error {
+	return rewriteRequests(root, func(*Request) {})
+}
+
+// v2 -> v3: the query of a request is kept in its params, and shown
+// in its URL. The query of saved URLs is moved to the params.
+func migrateQueryToParams(root string) error {
+	return rewriteRequests(root, func(r *Request) {
+		base, query, fragment := splitURL(r.URL)
+		if query != "" {
+			r.URL = base + fragment
+			r.Params = append(ParseQuery(query), r.Params...)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// copyFixture copies a workspace of testdata to a temporary directory
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "workspace")
+	if err := copyDir(filepath.Join("testdata", name), root); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func readFixtureRequest(t *testing.T, root, collection, id string) Request {
+	t.Helper()
+	store, err := NewRequestFileStore(filepath.Join(root, "collections", collection, "requests"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := store.GetRequest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestMigrateWorkspace(t *testing.T) {
+	root := copyFixture(t, "workspace-v0")
+	if err := MigrateWorkspace(root); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, exists, err := ReadWorkspaceManifest(root)
+	if err != nil || !exists {
+		t.Fatalf("ReadWorkspaceManifest() = %v, %v, %v", manifest, exists, err)
+	}
+	if manifest.Version != CurrentSchemaVersion {
+		t.Errorf("version = %d, want %d", manifest.Version, CurrentSchemaVersion)
+	}
+	backups, err := os.ReadDir(filepath.Join(root, "backups"))
+	if err != nil || len(backups) != 1 || !strings.HasSuffix(backups[0].Name(), "-v0") {
+		t.Errorf("backups = %v, %v", backups, err)
+	}
+
+	store, err := NewRequestFileStore(filepath.Join(root, "collections", "api", "requests"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := store.ReadCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"get-user", "list-users"}; !reflect.DeepEqual(catalog, want) {
+		t.Errorf("catalog = %q, want %q", catalog, want)
+	}
+
+	req := readFixtureRequest(t, root, "api", "list-users")
+	if want := "http://localhost:8080/users#top"; req.URL != want {
+		t.Errorf("URL = %q, want %q", req.URL, want)
+	}
+	wantParams := KVPairs{
+		{Key: "page", Value: "2", Enabled: true},
+		{Key: "q", Value: "a b", Enabled: true},
+		{Key: "tag", Value: "+1", Enabled: true},
+		{Key: "empty", Value: "", Enabled: true},
+		{Key: "flag", Value: "", Enabled: true, Bare: true},
+		{Key: "limit", Value: "10", Enabled: true},
+	}
+	if !reflect.DeepEqual(req.Params, wantParams) {
+		t.Errorf("Params = %+v, want %+v", req.Params, wantParams)
+	}
+	if want := "http://localhost:8080/users?page=2&q=a+b&tag=%2B1&empty=&flag&limit=10#top"; req.QueryURL() != want {
+		t.Errorf("QueryURL() = %q, want %q", req.QueryURL(), want)
+	}
+
+	// a second run has nothing to do
+	if err := MigrateWorkspace(root); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := os.ReadDir(filepath.Join(root, "backups")); len(backups) != 1 {
+		t.Errorf("%d backups after a second run, want 1", len(backups))
+	}
+}
+
+func TestReconcileCatalog(t *testing.T) {
+	requests := []Request{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	tests := []struct {
+		name    string
+		catalog []string
+		want    []string
+	}{
+		{"no catalog", nil, []string{"a", "b", "c"}},
+		{"ordered", []string{"c", "a", "b"}, []string{"c", "a", "b"}},
+		{"missing requests last", []string{"b"}, []string{"b", "a", "c"}},
+		{"deleted requests dropped", []string{"x", "c", "y"}, []string{"c", "a", "b"}},
+		{"duplicates dropped", []string{"b", "b", "a", "b"}, []string{"b", "a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reconcileCatalog(tt.catalog, requests); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reconcileCatalog() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateEnableKVPairs(t *testing.T) {
+	root := copyFixture(t, "workspace-v0")
+	if err := migrateEnableKVPairs(root); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(root, "collections", "api", "requests", "list-users")
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "enabled: true"); n != 2 {
+		t.Errorf("%d pairs are saved enabled, want 2:\n%s", n, data)
+	}
+	req := readFixtureRequest(t, root, "api", "list-users")
+	for _, kv := range append(req.Params, req.Headers...) {
+		if !kv.Enabled {
+			t.Errorf("%s is disabled", kv.Key)
+		}
+	}
+}
+
+func TestMigrateQueryToParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		params KVPairs
+		want   string
+		// the params after the migration
+		wantParams KVPairs
+	}{
+		{
+			name:       "no query",
+			url:        "http://x/users/:id",
+			params:     KVPairs{}.Add("a", "1"),
+			want:       "http://x/users/:id",
+			wantParams: KVPairs{}.Add("a", "1"),
+		},
+		{
+			name:   "query before params",
+			url:    "http://x/users?z=&flag&q=%2B#top",
+			params: KVPairs{{Key: "off", Value: "1"}},
+			want:   "http://x/users#top",
+			wantParams: KVPairs{
+				{Key: "z", Value: "", Enabled: true},
+				{Key: "flag", Value: "", Enabled: true, Bare: true},
+				{Key: "q", Value: "+", Enabled: true},
+				{Key: "off", Value: "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			store, err := NewRequestFileStore(filepath.Join(root, "collections", "api", "requests"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = store.CreateRequest(Request{ID: "req", Method: "GET", URL: tt.url, Params: tt.params})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := migrateQueryToParams(root); err != nil {
+				t.Fatal(err)
+			}
+			req, err := store.GetRequest("req")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.URL != tt.want {
+				t.Errorf("URL = %q, want %q", req.URL, tt.want)
+			}
+			if !reflect.DeepEqual(req.Params, tt.wantParams) {
+				t.Errorf("Params = %+v, want %+v", req.Params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestMigrateQueryToParamsSQLite(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewSQLiteStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := s.Requests(DEFAULT_COLLECTION_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.CreateRequest(Request{ID: "req", Method: "GET", URL: "http://x/users?page=2&z="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if err := migrateQueryToParams(root); err != nil {
+		t.Fatal(err)
+	}
+	s, err = NewSQLiteStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	store, err = s.Requests(DEFAULT_COLLECTION_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := store.GetRequest("req")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "http://x/users?page=2&z="; req.URL != "http://x/users" || req.QueryURL() != want {
+		t.Errorf("URL = %q and QueryURL() = %q, want %q", req.URL, req.QueryURL(), want)
+	}
+}
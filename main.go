@@ -1,34 +1,89 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gabrielfu/agora/internal"
 	"github.com/gabrielfu/agora/tui"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/styles"
 )
 
+var storageFlag = flag.String(
+	"storage",
+	"",
+	"storage backend of a new workspace: file, sqlite or memory (default file)",
+)
+
+var keybindingsFlag = flag.String(
+	"keybindings",
+	"",
+	"key binding config file (default $XDG_CONFIG_HOME/agora/keybindings.yaml)",
+)
+
+var themeFlag = flag.String(
+	"theme",
+	"auto",
+	"color theme: auto, dark, light, high-contrast, or a theme file in $XDG_CONFIG_HOME/agora/themes",
+)
+
+// loadKeymap loads the key binding config given by flag, or the default one if it exists
+func loadKeymap() (*keys.Keymap, error) {
+	if *keybindingsFlag != "" {
+		return keys.LoadConfig(*keybindingsFlag, true)
+	}
+	path, err := keys.DefaultConfigPath()
+	if err != nil {
+		return keys.Default(), nil
+	}
+	return keys.LoadConfig(path, false)
+}
+
 func Run() error {
-	var collectionStore *internal.CollectionStore
+	flag.Parse()
+
+	var rootDir string
 	var err error
-	if len(os.Args) > 1 {
-		rootDir := filepath.Join(os.Args[1], ".agora")
-		collectionStore, err = internal.NewCollectionStore(rootDir)
+	if flag.NArg() > 0 {
+		rootDir = filepath.Join(flag.Arg(0), ".agora")
 	} else {
-		collectionStore, err = internal.NewDefaultCollectionStore()
+		rootDir, err = internal.DefaultRootDir()
+		if err != nil {
+			return fmt.Errorf("error locating home directory: %v", err)
+		}
 	}
+
+	keymap, err := loadKeymap()
 	if err != nil {
-		return fmt.Errorf("error initializing collection store: %v", err)
+		return fmt.Errorf("error loading key bindings: %v", err)
+	}
+	keys.SetCurrent(keymap)
+
+	theme, err := styles.LoadTheme(*themeFlag)
+	if err != nil {
+		return fmt.Errorf("error loading theme: %v", err)
+	}
+	styles.SetTheme(theme)
+
+	storage, err := internal.OpenStorage(rootDir, *storageFlag)
+	if err != nil {
+		return fmt.Errorf("error initializing storage: %v", err)
+	}
+	defer storage.Close()
+	if err := internal.ExpireTrash(storage, time.Now().Add(-internal.TrashRetention)); err != nil {
+		return fmt.Errorf("error expiring trash: %v", err)
 	}
 
-	collectionRequestDir := collectionStore.CurrentCollectionRequestDir()
-	requestStore, err := internal.NewRequestFileStore(collectionRequestDir)
+	model, err := tui.NewRootModel(storage, tui.WithCollectionPaneWidth(0.33))
 	if err != nil {
 		return fmt.Errorf("error initializing collection store: %v", err)
 	}
-	model := tui.NewRootModel(collectionStore, requestStore, tui.WithCollectionPaneWidth(0.33))
+	defer model.Close()
 	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err = program.Run()
 	return err
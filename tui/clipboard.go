@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// writeClipboard copies text to the system clipboard. Over SSH, or where
+// there is no system clipboard, the terminal is asked to copy the text
+// with the OSC52 escape sequence, which also works in tmux and screen.
+func writeClipboard(text string) error {
+	if os.Getenv("SSH_TTY") == "" && !clipboard.Unsupported {
+		if err := clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	// the sequence is written to stderr, not to interleave
+	// with the rendering of the program to stdout
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
@@ -0,0 +1,90 @@
+package dialogs
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/messages"
+	"github.com/gabrielfu/agora/tui/styles"
+	"github.com/gabrielfu/agora/tui/views"
+)
+
+// ConfirmDialog asks the user to confirm an action
+type ConfirmDialog struct {
+	width      int
+	maxWidth   int
+	title      []string
+	message    string
+	confirmCmd tea.Cmd // Cmd to run when the action is confirmed
+	exitView   views.View
+}
+
+func NewConfirmDialog(maxWidth int, title []string, exitView views.View) ConfirmDialog {
+	return ConfirmDialog{
+		width:    maxWidth,
+		maxWidth: maxWidth,
+		title:    title,
+		exitView: exitView,
+	}
+}
+
+func (m *ConfirmDialog) SetMessage(message string) {
+	m.message = message
+}
+
+func (m *ConfirmDialog) SetConfirmCmd(cmd tea.Cmd) {
+	m.confirmCmd = cmd
+}
+
+// footer shows the keys that confirm and cancel, e.g. "y/n"
+func (m ConfirmDialog) footer() []string {
+	var bound []string
+	for _, action := range []keys.Action{keys.Confirm, keys.Cancel} {
+		if k := keys.Keys(keys.ConfirmDialogScope, action); len(k) > 0 {
+			bound = append(bound, keys.FormatKey(k[0]))
+		}
+	}
+	if len(bound) == 0 {
+		return nil
+	}
+	return []string{strings.Join(bound, "/")}
+}
+
+func (m ConfirmDialog) generateStyle() lipgloss.Style {
+	border := styles.GenerateBorder(
+		lipgloss.RoundedBorder(),
+		styles.GenerateBorderOption{Title: m.title, Footer: m.footer()},
+		m.width,
+	)
+	return lipgloss.NewStyle().
+		BorderStyle(border).
+		BorderForeground(lipgloss.Color(styles.StatusErrorColor)).
+		Width(m.width).
+		Padding(0, 1)
+}
+
+func (m ConfirmDialog) exit() tea.Cmd {
+	return messages.ExitDialogCmd(m.exitView)
+}
+
+func (m *ConfirmDialog) SetWidth(windowWidth int) {
+	m.width = min(m.maxWidth, windowWidth-4)
+}
+
+func (m *ConfirmDialog) SetHeight(int) {}
+
+func (m *ConfirmDialog) Update(msg tea.Msg) (any, tea.Cmd) {
+	switch keys.Lookup(keys.ConfirmDialogScope, msg) {
+	case keys.Confirm:
+		return m, tea.Batch(m.exit(), m.confirmCmd)
+	case keys.Cancel:
+		return m, m.exit()
+	}
+	return m, nil
+}
+
+func (m *ConfirmDialog) View() string {
+	return m.generateStyle().Render(m.message)
+}
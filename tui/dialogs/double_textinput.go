@@ -4,6 +4,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
@@ -23,13 +24,17 @@ type DoubleTextInputDialog struct {
 	upperTextInput textinput.Model
 	lowerTextInput textinput.Model
 	focusUpper     bool
+	// returns the suggestions of the lower input for the value of the upper one
+	lowerSuggestions func(string) []string
 }
 
 func NewDoubleTextInputDialog(maxWidth int, upperTitle, upperFooter, lowerTitle, lowerFooter []string, submitCmdFunc DoubleTextInputCmdFunc, exitView views.View) DoubleTextInputDialog {
 	upper := textinput.New()
 	upper.Prompt = ""
+	applySuggestionKeys(&upper)
 	lower := textinput.New()
 	lower.Prompt = ""
+	applySuggestionKeys(&lower)
 	return DoubleTextInputDialog{
 		width:          maxWidth,
 		maxWidth:       maxWidth,
@@ -55,10 +60,22 @@ func (m *DoubleTextInputDialog) SetLowerPrompt(prompt string) {
 
 func (m *DoubleTextInputDialog) SetUpperValue(value string) {
 	m.upperTextInput.SetValue(value)
+	m.upperTextInput.CursorEnd()
 }
 
 func (m *DoubleTextInputDialog) SetLowerValue(value string) {
 	m.lowerTextInput.SetValue(value)
+	m.lowerTextInput.CursorEnd()
+}
+
+// SetSuggestions sets the completions of the upper input, and of the lower
+// input depending on the value of the upper one. nil disables them.
+func (m *DoubleTextInputDialog) SetSuggestions(upper []string, lower func(string) []string) {
+	m.upperTextInput.ShowSuggestions = len(upper) > 0
+	m.upperTextInput.SetSuggestions(upper)
+	m.lowerSuggestions = lower
+	m.lowerTextInput.ShowSuggestions = false
+	m.lowerTextInput.SetSuggestions(nil)
 }
 
 func (m *DoubleTextInputDialog) FocusUpper() {
@@ -71,19 +88,32 @@ func (m *DoubleTextInputDialog) FocusLower() {
 	m.upperTextInput.Blur()
 	m.lowerTextInput.Focus()
 	m.focusUpper = false
+	if m.lowerSuggestions != nil {
+		suggestions := m.lowerSuggestions(m.upperTextInput.Value())
+		m.lowerTextInput.ShowSuggestions = len(suggestions) > 0
+		m.lowerTextInput.SetSuggestions(suggestions)
+	}
 }
 
 func (m DoubleTextInputDialog) generateStyle(upper bool) lipgloss.Style {
 	var title, footer []string
-	var color string
 	if upper {
 		title = m.upperTitle
 		footer = m.upperFooter
-		color = styles.FocusBorderColor
 	} else {
 		title = m.lowerTitle
 		footer = m.lowerFooter
-		color = styles.DefaultBorderColor
+	}
+	color := styles.DefaultBorderColor
+	if upper == m.focusUpper {
+		color = styles.FocusBorderColor
+		input := m.lowerTextInput
+		if upper {
+			input = m.upperTextInput
+		}
+		if hint := suggestionHint(input); hint != "" {
+			footer = append(footer[:len(footer):len(footer)], hint)
+		}
 	}
 	border := styles.GenerateBorder(
 		lipgloss.RoundedBorder(),
@@ -113,14 +143,15 @@ func (m *DoubleTextInputDialog) SetCmdFunc(cmdFunc DoubleTextInputCmdFunc) {
 
 func (m *DoubleTextInputDialog) updateUpper(msg tea.Msg) (any, tea.Cmd) {
 	var cmd tea.Cmd
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			m.FocusLower()
-		case tea.KeyCtrlC, tea.KeyEsc:
-			return m, m.exit()
-		}
+	switch keys.Lookup(keys.TextInputDialogScope, msg) {
+	case keys.Submit, keys.CursorDown:
+		m.FocusLower()
+		return m, textinput.Blink
+	case keys.Complete:
+		completeSuggestion(&m.upperTextInput)
+		return m, nil
+	case keys.Cancel:
+		return m, m.exit()
 	}
 	m.upperTextInput, cmd = m.upperTextInput.Update(msg)
 	return m, tea.Batch(textinput.Blink, cmd)
@@ -128,16 +159,19 @@ func (m *DoubleTextInputDialog) updateUpper(msg tea.Msg) (any, tea.Cmd) {
 
 func (m *DoubleTextInputDialog) updateLower(msg tea.Msg) (any, tea.Cmd) {
 	var cmd tea.Cmd
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			upper := m.upperTextInput.Value()
-			lower := m.lowerTextInput.Value()
-			return m, tea.Batch(m.exit(), m.submitCmdFunc(upper, lower))
-		case tea.KeyCtrlC, tea.KeyEsc:
-			return m, m.exit()
-		}
+	switch keys.Lookup(keys.TextInputDialogScope, msg) {
+	case keys.Submit:
+		upper := m.upperTextInput.Value()
+		lower := m.lowerTextInput.Value()
+		return m, tea.Batch(m.exit(), m.submitCmdFunc(upper, lower))
+	case keys.CursorUp:
+		m.FocusUpper()
+		return m, textinput.Blink
+	case keys.Complete:
+		completeSuggestion(&m.lowerTextInput)
+		return m, nil
+	case keys.Cancel:
+		return m, m.exit()
 	}
 	m.lowerTextInput, cmd = m.lowerTextInput.Update(msg)
 	return m, tea.Batch(textinput.Blink, cmd)
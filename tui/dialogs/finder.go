@@ -0,0 +1,234 @@
+package dialogs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/internal"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/messages"
+	"github.com/gabrielfu/agora/tui/styles"
+	"github.com/gabrielfu/agora/tui/views"
+	"github.com/mattn/go-runewidth"
+)
+
+func finderMatchStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(styles.KeyColor))
+}
+
+func finderHintStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(styles.DefaultBorderColor))
+}
+
+// FinderItem is an entry of a FinderDialog
+type FinderItem struct {
+	Label string
+	// Hint is displayed dimmed at the end of the row and is also searched
+	Hint string
+	// Cmd is run when the item is selected
+	Cmd tea.Cmd
+}
+
+func (i FinderItem) searchText() string {
+	if i.Hint == "" {
+		return i.Label
+	}
+	return i.Label + " " + i.Hint
+}
+
+// FinderDialog lets the user fuzzy search a list of items
+// and runs the command of the selected one
+type FinderDialog struct {
+	width     int
+	maxWidth  int
+	height    int
+	maxHeight int
+	title     []string
+	exitView  views.View
+	textInput textinput.Model
+	items     []FinderItem
+	matches   []internal.FuzzyMatch
+	cursor    int
+	offset    int // index of the first visible match
+}
+
+func NewFinderDialog(maxWidth, maxHeight int, title []string, exitView views.View) FinderDialog {
+	t := textinput.New()
+	t.Prompt = "> "
+	return FinderDialog{
+		width:     maxWidth,
+		maxWidth:  maxWidth,
+		height:    maxHeight,
+		maxHeight: maxHeight,
+		title:     title,
+		exitView:  exitView,
+		textInput: t,
+	}
+}
+
+func (m *FinderDialog) SetTitle(title []string) {
+	m.title = title
+}
+
+func (m *FinderDialog) SetExitView(exitView views.View) {
+	m.exitView = exitView
+}
+
+// SetItems sets the items to search and clears the query.
+func (m *FinderDialog) SetItems(items []FinderItem) {
+	m.items = items
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	m.filter()
+}
+
+func (m *FinderDialog) filter() {
+	targets := make([]string, len(m.items))
+	for i, item := range m.items {
+		targets[i] = item.searchText()
+	}
+	m.matches = internal.FuzzyFind(m.textInput.Value(), targets)
+	m.cursor = 0
+	m.offset = 0
+}
+
+// listHeight is the number of visible matches
+func (m FinderDialog) listHeight() int {
+	// the text input and the separator take two lines
+	return max(1, m.height-2)
+}
+
+func (m *FinderDialog) moveCursor(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.cursor = max(0, min(len(m.matches)-1, m.cursor+delta))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m FinderDialog) generateStyle() lipgloss.Style {
+	border := styles.GenerateBorder(
+		lipgloss.RoundedBorder(),
+		styles.GenerateBorderOption{
+			Title:  m.title,
+			Footer: []string{fmt.Sprintf("%d / %d", len(m.matches), len(m.items))},
+		},
+		m.width,
+	)
+	return lipgloss.NewStyle().
+		BorderStyle(border).
+		BorderForeground(lipgloss.Color(styles.FocusBorderColor)).
+		Width(m.width).
+		Padding(0, 1)
+}
+
+func (m FinderDialog) exit() tea.Cmd {
+	return messages.ExitDialogCmd(m.exitView)
+}
+
+func (m *FinderDialog) SetWidth(windowWidth int) {
+	m.width = min(m.maxWidth, windowWidth-4)
+	m.textInput.Width = m.width - 2 - len(m.textInput.Prompt)
+}
+
+func (m *FinderDialog) SetHeight(windowHeight int) {
+	m.height = min(m.maxHeight, windowHeight-4)
+	m.moveCursor(0)
+}
+
+func (m *FinderDialog) Update(msg tea.Msg) (any, tea.Cmd) {
+	switch keys.Lookup(keys.FinderDialogScope, msg) {
+	case keys.Select:
+		if m.cursor >= len(m.matches) {
+			return m, m.exit()
+		}
+		item := m.items[m.matches[m.cursor].Index]
+		// exit first, as the command may depend on the focus
+		return m, tea.Sequence(m.exit(), item.Cmd)
+	case keys.Cancel:
+		return m, m.exit()
+	case keys.CursorUp:
+		m.moveCursor(-1)
+		return m, nil
+	case keys.CursorDown:
+		m.moveCursor(1)
+		return m, nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "pgup":
+			m.moveCursor(-m.listHeight())
+			return m, nil
+		case "pgdown":
+			m.moveCursor(m.listHeight())
+			return m, nil
+		}
+	}
+	query := m.textInput.Value()
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	if m.textInput.Value() != query {
+		m.filter()
+	}
+	return m, tea.Batch(textinput.Blink, cmd)
+}
+
+// renderRow renders an item with the matched characters highlighted
+func (m FinderDialog) renderRow(match internal.FuzzyMatch, selected bool) string {
+	item := m.items[match.Index]
+	base := itemStyle
+	if selected {
+		base = selectedItemStyle()
+	}
+	highlight := finderMatchStyle().Inherit(base)
+	hintStyle := finderHintStyle().Inherit(base)
+
+	matched := make(map[int]bool, len(match.MatchedIndexes))
+	for _, i := range match.MatchedIndexes {
+		matched[i] = true
+	}
+
+	width := m.width - 2
+	hint := runewidth.Truncate(item.Hint, width/2, "…")
+	labelWidth := width - runewidth.StringWidth(hint)
+	if hint != "" {
+		labelWidth--
+	}
+	label := runewidth.Truncate(item.Label, labelWidth, "…")
+
+	var b strings.Builder
+	for i, r := range label {
+		if matched[i] {
+			b.WriteString(highlight.Render(string(r)))
+		} else {
+			b.WriteString(base.Render(string(r)))
+		}
+	}
+	padding := width - runewidth.StringWidth(label) - runewidth.StringWidth(hint)
+	b.WriteString(base.Render(strings.Repeat(" ", max(0, padding))))
+	b.WriteString(hintStyle.Render(hint))
+	return b.String()
+}
+
+func (m *FinderDialog) View() string {
+	lines := []string{
+		m.textInput.View(),
+		finderHintStyle().Render(strings.Repeat("─", max(0, m.width-2))),
+	}
+	if len(m.matches) == 0 {
+		lines = append(lines, "No matches")
+	}
+	end := min(len(m.matches), m.offset+m.listHeight())
+	for i := m.offset; i < end; i++ {
+		lines = append(lines, m.renderRow(m.matches[i], i == m.cursor))
+	}
+	return m.generateStyle().Render(strings.Join(lines, "\n"))
+}
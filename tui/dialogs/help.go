@@ -0,0 +1,156 @@
+package dialogs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/messages"
+	"github.com/gabrielfu/agora/tui/styles"
+	"github.com/gabrielfu/agora/tui/views"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	helpColumnWidth = 40
+	helpColumnGap   = 3
+)
+
+// HelpDialog shows every key binding of the current keymap,
+// grouped by pane and dialog
+type HelpDialog struct {
+	width    int
+	height   int
+	exitView views.View
+	viewport viewport.Model
+}
+
+func NewHelpDialog(exitView views.View) HelpDialog {
+	v := viewport.New(0, 0)
+	v.KeyMap = viewport.KeyMap{
+		PageDown: key.NewBinding(key.WithKeys("pgdown")),
+		PageUp:   key.NewBinding(key.WithKeys("pgup")),
+	}
+	return HelpDialog{
+		exitView: exitView,
+		viewport: v,
+	}
+}
+
+func (m *HelpDialog) SetExitView(exitView views.View) {
+	m.exitView = exitView
+}
+
+func (m HelpDialog) exit() tea.Cmd {
+	return messages.ExitDialogCmd(m.exitView)
+}
+
+func (m *HelpDialog) SetWidth(windowWidth int) {
+	m.width = windowWidth - 4
+	m.viewport.Width = m.width - 2
+	m.render()
+}
+
+func (m *HelpDialog) SetHeight(windowHeight int) {
+	m.height = windowHeight - 4
+	m.viewport.Height = max(1, m.height)
+	m.render()
+}
+
+// renderSection renders the bindings of a section in a column
+func renderSection(section keys.HelpSection, width int) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(styles.FocusBorderColor))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.KeyColor))
+
+	keyWidth := 0
+	for _, item := range section.Items {
+		keyWidth = max(keyWidth, runewidth.StringWidth(item.Key))
+	}
+	keyWidth = min(keyWidth, width/2)
+
+	lines := []string{titleStyle.Render(runewidth.Truncate(section.Title, width, "…"))}
+	for _, item := range section.Items {
+		k := runewidth.FillRight(runewidth.Truncate(item.Key, keyWidth, "…"), keyWidth)
+		desc := runewidth.Truncate(item.Description, max(0, width-keyWidth-2), "…")
+		lines = append(lines, keyStyle.Render(k)+"  "+desc)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// render lays the sections out in as many columns as fit the width,
+// adding each section to the shortest column
+func (m *HelpDialog) render() {
+	width := m.viewport.Width
+	columns := max(1, (width+helpColumnGap)/(helpColumnWidth+helpColumnGap))
+	columnWidth := (width - helpColumnGap*(columns-1)) / columns
+	if columnWidth <= 0 {
+		return
+	}
+
+	blocks := make([][]string, columns)
+	heights := make([]int, columns)
+	for _, section := range keys.Current().FullHelp() {
+		shortest := 0
+		for i := range heights {
+			if heights[i] < heights[shortest] {
+				shortest = i
+			}
+		}
+		block := renderSection(section, columnWidth)
+		blocks[shortest] = append(blocks[shortest], block)
+		heights[shortest] += lipgloss.Height(block) + 1
+	}
+
+	rendered := make([]string, 0, 2*columns)
+	for i, column := range blocks {
+		if i > 0 {
+			rendered = append(rendered, strings.Repeat(" ", helpColumnGap))
+		}
+		rendered = append(rendered, lipgloss.NewStyle().Width(columnWidth).Render(strings.Join(column, "\n\n")))
+	}
+	m.viewport.SetContent(lipgloss.JoinHorizontal(lipgloss.Top, rendered...))
+}
+
+func (m HelpDialog) generateStyle() lipgloss.Style {
+	var footer []string
+	if m.viewport.TotalLineCount() > m.viewport.Height {
+		footer = []string{fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100)}
+	}
+	border := styles.GenerateBorder(
+		lipgloss.RoundedBorder(),
+		styles.GenerateBorderOption{
+			Title:  []string{"Key", "bindings"},
+			Footer: footer,
+		},
+		m.width,
+	)
+	return lipgloss.NewStyle().
+		BorderStyle(border).
+		BorderForeground(lipgloss.Color(styles.FocusBorderColor)).
+		Width(m.width).
+		Padding(0, 1)
+}
+
+func (m *HelpDialog) Update(msg tea.Msg) (any, tea.Cmd) {
+	switch keys.Lookup(keys.HelpDialogScope, msg) {
+	case keys.Cancel:
+		return m, m.exit()
+	case keys.CursorUp:
+		m.viewport.LineUp(1)
+		return m, nil
+	case keys.CursorDown:
+		m.viewport.LineDown(1)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *HelpDialog) View() string {
+	return m.generateStyle().Render(m.viewport.View())
+}
@@ -0,0 +1,165 @@
+package dialogs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/messages"
+	"github.com/gabrielfu/agora/tui/styles"
+	"github.com/gabrielfu/agora/tui/views"
+	"github.com/mattn/go-runewidth"
+)
+
+type SelectCmdFunc func(string) tea.Cmd
+
+type option struct {
+	label string
+	value string
+}
+
+func (o option) FilterValue() string { return o.label }
+
+type optionDelegate struct {
+	width int
+}
+
+func (d optionDelegate) Height() int                             { return 1 }
+func (d optionDelegate) Spacing() int                            { return 0 }
+func (d optionDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d optionDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	o, ok := listItem.(option)
+	if !ok {
+		return
+	}
+	text := runewidth.FillRight(runewidth.Truncate(o.label, d.width, "…"), d.width)
+	fn := itemStyle.Render
+	if index == m.Index() {
+		fn = selectedItemStyle().Render
+	}
+	fmt.Fprint(w, fn(text))
+}
+
+// SelectDialog lets the user pick one of a list of options
+type SelectDialog struct {
+	width         int
+	maxWidth      int
+	height        int
+	maxHeight     int
+	title         []string
+	submitCmdFunc SelectCmdFunc // func to generate a Cmd that submits the selected option
+	exitView      views.View
+	list          list.Model
+}
+
+func NewSelectDialog(maxWidth, maxHeight int, title []string, submitCmdFunc SelectCmdFunc, exitView views.View) SelectDialog {
+	l := list.New([]list.Item{}, optionDelegate{width: maxWidth - 2}, maxWidth, maxHeight)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.SetShowPagination(false)
+	l.SetShowFilter(false)
+	applyListKeys(&l)
+	return SelectDialog{
+		width:         maxWidth,
+		maxWidth:      maxWidth,
+		height:        maxHeight,
+		maxHeight:     maxHeight,
+		title:         title,
+		submitCmdFunc: submitCmdFunc,
+		exitView:      exitView,
+		list:          l,
+	}
+}
+
+// applyListKeys binds the cursor movement of a list to the select dialog keys
+func applyListKeys(l *list.Model) {
+	l.KeyMap.CursorUp.SetKeys(keys.Keys(keys.SelectDialogScope, keys.CursorUp)...)
+	l.KeyMap.CursorDown.SetKeys(keys.Keys(keys.SelectDialogScope, keys.CursorDown)...)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ForceQuit.SetEnabled(false)
+}
+
+func (m *SelectDialog) SetTitle(title []string) {
+	m.title = title
+}
+
+func (m *SelectDialog) SetOptions(options []string) {
+	m.SetLabeledOptions(options, options)
+}
+
+// SetLabeledOptions sets options that display labels,
+// but submit the value of the same index.
+func (m *SelectDialog) SetLabeledOptions(labels, values []string) {
+	items := make([]list.Item, len(labels))
+	for i := range labels {
+		items[i] = option{label: labels[i], value: values[i]}
+	}
+	m.list.SetItems(items)
+	m.list.Select(0)
+	m.resize()
+}
+
+func (m *SelectDialog) SetCmdFunc(cmdFunc SelectCmdFunc) {
+	m.submitCmdFunc = cmdFunc
+}
+
+func (m *SelectDialog) resize() {
+	m.list.SetDelegate(optionDelegate{width: m.width - 2})
+	m.list.SetWidth(m.width)
+	m.list.SetHeight(max(1, min(m.height, len(m.list.Items()))))
+}
+
+func (m SelectDialog) generateStyle() lipgloss.Style {
+	border := styles.GenerateBorder(
+		lipgloss.RoundedBorder(),
+		styles.GenerateBorderOption{Title: m.title},
+		m.width,
+	)
+	return lipgloss.NewStyle().
+		BorderStyle(border).
+		BorderForeground(lipgloss.Color(styles.FocusBorderColor)).
+		Width(m.width).
+		Padding(0, 1)
+}
+
+func (m SelectDialog) exit() tea.Cmd {
+	return messages.ExitDialogCmd(m.exitView)
+}
+
+func (m *SelectDialog) SetWidth(windowWidth int) {
+	m.width = min(m.maxWidth, windowWidth-4)
+	m.resize()
+}
+
+func (m *SelectDialog) SetHeight(windowHeight int) {
+	m.height = min(m.maxHeight, windowHeight-4)
+	m.resize()
+}
+
+func (m *SelectDialog) Update(msg tea.Msg) (any, tea.Cmd) {
+	switch keys.Lookup(keys.SelectDialogScope, msg) {
+	case keys.Select:
+		selected, ok := m.list.SelectedItem().(option)
+		if !ok {
+			return m, m.exit()
+		}
+		return m, tea.Batch(m.exit(), m.submitCmdFunc(selected.value))
+	case keys.Cancel:
+		return m, m.exit()
+	}
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *SelectDialog) View() string {
+	if len(m.list.Items()) == 0 {
+		return m.generateStyle().Render("No options")
+	}
+	return m.generateStyle().Render(m.list.View())
+}
@@ -3,20 +3,26 @@ package dialogs
 import (
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gabrielfu/agora/internal"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
 )
 
-var (
-	itemStyle         = lipgloss.NewStyle()
-	selectedItemStyle = lipgloss.NewStyle().Background(lipgloss.Color(styles.SelectedBackgroundColor))
-)
+var itemStyle = lipgloss.NewStyle()
+
+func selectedItemStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(styles.SelectedForegroundColor)).
+		Background(lipgloss.Color(styles.SelectedBackgroundColor))
+}
 
 type item string
 
@@ -34,50 +40,89 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	}
 	method := fmt.Sprintf("%-8s", string(it))
 	color := styles.GetMethodColor(string(it))
+	if it == otherMethodItem {
+		color = styles.HintColor
+	}
 	fn := itemStyle.Foreground(lipgloss.Color(color)).Render
 	if index == m.Index() {
-		fn = selectedItemStyle.Render
+		fn = selectedItemStyle().Render
 	}
 	fmt.Fprint(w, fn(method))
 }
 
-var methods = []list.Item{
-	item("GET"),
-	item("POST"),
-	item("PUT"),
-	item("PATCH"),
-	item("DELETE"),
-	item("HEAD"),
-	item("OPTIONS"),
-}
+// otherMethodItem is the last item of the dialog, to type any other method
+const otherMethodItem = item("Other…")
+
+// maximum number of methods shown at once
+const maxMethodListHeight = 12
 
 type SelectMethodDialog struct {
 	width int
 	list  list.Model
+	// typing is set when a method is typed instead of selected
+	typing bool
+	input  textinput.Model
+	err    string
 }
 
 func NewSelectMethodDialog() SelectMethodDialog {
-	width := 10
-	l := list.New(methods, itemDelegate{}, width, 7)
+	l := list.New(nil, itemDelegate{}, 0, 0)
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
 	l.SetShowPagination(false)
 	l.SetShowFilter(false)
-	return SelectMethodDialog{list: l, width: width}
+	applyListKeys(&l)
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 32
+	input.ShowSuggestions = true
+	input.SetSuggestions(internal.ExtendedMethods)
+	applySuggestionKeys(&input)
+	m := SelectMethodDialog{list: l, input: input}
+	m.SetMethods(nil, "")
+	return m
+}
+
+// SetMethods lists the standard methods followed by the custom ones,
+// with the cursor on the current method of the request
+func (m *SelectMethodDialog) SetMethods(custom []string, current string) {
+	methods := append(slices.Clone(internal.StandardMethods), custom...)
+	if current != "" && !slices.Contains(methods, current) {
+		methods = append(methods, current)
+	}
+	items := make([]list.Item, 0, len(methods)+1)
+	width := 10
+	for _, method := range methods {
+		items = append(items, item(method))
+		width = max(width, lipgloss.Width(method)+2)
+	}
+	items = append(items, otherMethodItem)
+	m.width = width
+	m.list.SetItems(items)
+	m.list.SetSize(width, min(len(items), maxMethodListHeight))
+	m.list.Select(max(0, slices.Index(methods, current)))
+	m.typing = false
+	m.err = ""
 }
 
 func (m SelectMethodDialog) generateStyle() lipgloss.Style {
-	border := styles.GenerateBorder(
-		lipgloss.RoundedBorder(),
-		styles.GenerateBorderOption{Title: []string{"Method"}},
-		m.width,
-	)
+	option := styles.GenerateBorderOption{Title: []string{"Method"}}
+	width := m.width
+	if m.typing {
+		width = 40
+		if m.err != "" {
+			option.Footer = []string{m.err}
+		} else if hint := suggestionHint(m.input); hint != "" {
+			option.Footer = []string{hint}
+		}
+	}
+	border := styles.GenerateBorder(lipgloss.RoundedBorder(), option, width)
 	return lipgloss.NewStyle().
 		BorderStyle(border).
 		BorderForeground(lipgloss.Color(styles.FocusBorderColor)).
-		Width(m.width).
+		Width(width).
 		Padding(0, 1)
 }
 
@@ -85,26 +130,59 @@ func (m SelectMethodDialog) exit() tea.Cmd {
 	return messages.ExitDialogCmd(views.UrlPaneView)
 }
 
-func (m SelectMethodDialog) updateRequest() tea.Cmd {
-	method := string(m.list.SelectedItem().(item))
+func (m SelectMethodDialog) updateRequest(method string) tea.Cmd {
 	return messages.UpdateRequestCmd(func(r *internal.Request) {
 		r.Method = method
 	})
 }
 
+// Typing reports whether a method is being typed
+func (m SelectMethodDialog) Typing() bool {
+	return m.typing
+}
+
 func (m *SelectMethodDialog) SetWidth(width int) {}
 
 func (m *SelectMethodDialog) SetHeight(width int) {}
 
+func (m *SelectMethodDialog) updateInput(msg tea.Msg) (any, tea.Cmd) {
+	switch keys.Lookup(keys.TextInputDialogScope, msg) {
+	case keys.Submit:
+		method, err := internal.NormalizeMethod(m.input.Value())
+		if err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		return m, tea.Batch(m.exit(), m.updateRequest(method), messages.AddCustomMethodCmd(method))
+	case keys.Complete:
+		completeSuggestion(&m.input)
+		return m, nil
+	case keys.Cancel:
+		m.typing = false
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.err = ""
+	return m, cmd
+}
+
 func (m *SelectMethodDialog) Update(msg tea.Msg) (any, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			return m, tea.Batch(m.exit(), m.updateRequest())
-		case tea.KeyCtrlC, tea.KeyEsc:
-			return m, m.exit()
+	if m.typing {
+		return m.updateInput(msg)
+	}
+	switch keys.Lookup(keys.SelectDialogScope, msg) {
+	case keys.Select:
+		selected := m.list.SelectedItem().(item)
+		if selected == otherMethodItem {
+			m.typing = true
+			m.input.SetValue("")
+			m.input.Focus()
+			return m, textinput.Blink
 		}
+		return m, tea.Batch(m.exit(), m.updateRequest(string(selected)))
+	case keys.Cancel:
+		return m, m.exit()
 	}
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
@@ -112,5 +190,8 @@ func (m *SelectMethodDialog) Update(msg tea.Msg) (any, tea.Cmd) {
 }
 
 func (m *SelectMethodDialog) View() string {
+	if m.typing {
+		return m.generateStyle().Render(m.input.View())
+	}
 	return m.generateStyle().Render(m.list.View())
 }
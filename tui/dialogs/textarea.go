@@ -4,6 +4,7 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
@@ -26,8 +27,9 @@ type TextAreaDialog struct {
 func NewTextAreaDialog(maxWidth, maxHeight int, title, footer []string, submitCmdFunc TextAreaCmdFunc, exitView views.View) TextAreaDialog {
 	t := textarea.New()
 	t.Prompt = ""
-	t.FocusedStyle.LineNumber = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	t.BlurredStyle.LineNumber = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	t.CharLimit = 0
+	t.FocusedStyle.LineNumber = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.HintColor))
+	t.BlurredStyle.LineNumber = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.HintColor))
 	return TextAreaDialog{
 		width:         maxWidth,
 		maxWidth:      maxWidth,
@@ -41,6 +43,11 @@ func NewTextAreaDialog(maxWidth, maxHeight int, title, footer []string, submitCm
 	}
 }
 
+func (m *TextAreaDialog) SetTitle(title, footer []string) {
+	m.title = title
+	m.footer = footer
+}
+
 func (m *TextAreaDialog) SetValue(value string) {
 	m.textArea.SetValue(value)
 }
@@ -81,16 +88,14 @@ func (m *TextAreaDialog) SetCmdFunc(cmdFunc TextAreaCmdFunc) {
 
 func (m *TextAreaDialog) Update(msg tea.Msg) (any, tea.Cmd) {
 	var cmd tea.Cmd
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+w":
-			return m, tea.Batch(m.exit(), m.submitCmdFunc(m.textArea.Value()))
-		case "tab":
-			m.textArea.InsertString("  ")
-		case "ctrl+c", "esc":
-			return m, m.exit()
-		}
+	switch keys.Lookup(keys.TextAreaDialogScope, msg) {
+	case keys.Submit:
+		return m, tea.Batch(m.exit(), m.submitCmdFunc(m.textArea.Value()))
+	case keys.Cancel:
+		return m, m.exit()
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyTab {
+		m.textArea.InsertString("  ")
 	}
 	m.textArea, cmd = m.textArea.Update(msg)
 	return m, tea.Batch(textarea.Blink, cmd)
@@ -1,9 +1,13 @@
 package dialogs
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
@@ -41,6 +45,7 @@ func (m *TextInputDialog) SetPrompt(prompt string) {
 
 func (m *TextInputDialog) SetValue(value string) {
 	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
 }
 
 func (m *TextInputDialog) Focus() {
@@ -76,14 +81,11 @@ func (m *TextInputDialog) SetCmdFunc(cmdFunc TextInputCmdFunc) {
 
 func (m *TextInputDialog) Update(msg tea.Msg) (any, tea.Cmd) {
 	var cmd tea.Cmd
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			return m, tea.Batch(m.exit(), m.submitCmdFunc(m.textInput.Value()))
-		case tea.KeyCtrlC, tea.KeyEsc:
-			return m, m.exit()
-		}
+	switch keys.Lookup(keys.TextInputDialogScope, msg) {
+	case keys.Submit:
+		return m, tea.Batch(m.exit(), m.submitCmdFunc(m.textInput.Value()))
+	case keys.Cancel:
+		return m, m.exit()
 	}
 	m.textInput, cmd = m.textInput.Update(msg)
 	return m, tea.Batch(textinput.Blink, cmd)
@@ -92,3 +94,42 @@ func (m *TextInputDialog) Update(msg tea.Msg) (any, tea.Cmd) {
 func (m *TextInputDialog) View() string {
 	return m.generateStyle().Render(m.textInput.View())
 }
+
+// applySuggestionKeys binds the choice of a suggestion to the keys
+// of the text input dialogs. Suggestions are completed by the dialogs.
+func applySuggestionKeys(t *textinput.Model) {
+	t.KeyMap.AcceptSuggestion.SetEnabled(false)
+	t.KeyMap.NextSuggestion.SetKeys(keys.Keys(keys.TextInputDialogScope, keys.NextSuggestion)...)
+	t.KeyMap.PrevSuggestion.SetKeys(keys.Keys(keys.TextInputDialogScope, keys.PrevSuggestion)...)
+}
+
+// completeSuggestion replaces the value with the current suggestion, in
+// the case of the suggestion, which the text input would keep as typed
+func completeSuggestion(t *textinput.Model) {
+	if suggestion := t.CurrentSuggestion(); suggestion != "" {
+		t.SetValue(suggestion)
+		t.CursorEnd()
+	}
+}
+
+// suggestionHint tells the keys to complete the value, if there are suggestions
+func suggestionHint(t textinput.Model) string {
+	if !t.ShowSuggestions || t.CurrentSuggestion() == "" {
+		return ""
+	}
+	completeKeys := keys.Keys(keys.TextInputDialogScope, keys.Complete)
+	if len(completeKeys) == 0 {
+		return ""
+	}
+	hint := keys.FormatKey(completeKeys[0]) + " to complete"
+	matches := 0
+	for _, s := range t.AvailableSuggestions() {
+		if strings.HasPrefix(strings.ToLower(s), strings.ToLower(t.Value())) {
+			matches++
+		}
+	}
+	if nextKeys := keys.Keys(keys.TextInputDialogScope, keys.NextSuggestion); matches > 1 && len(nextKeys) > 0 {
+		hint += fmt.Sprintf(", %s for %d more", keys.FormatKey(nextKeys[0]), matches-1)
+	}
+	return hint
+}
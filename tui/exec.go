@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gabrielfu/agora/internal"
+)
+
+// requestProgressMsg reports the progress of the download of a response
+type requestProgressMsg struct {
+	sequence int
+	received int64
+	total    int64
+	updates  <-chan tea.Msg
+}
+
+// requestDoneMsg is the result of sending a request
+type requestDoneMsg struct {
+	sequence int
+	resp     *internal.Response
+	err      error
+	duration time.Duration
+}
+
+// execRequest sends the current request in the background. The progress
+// of the download is reported until the response is read.
+func (m *RootModel) execRequest() tea.Cmd {
+	if m.rctx.Empty() {
+		return nil
+	}
+	req := m.rctx.Request().Copy()
+	ctx, sequence := m.rctx.Start()
+	updates := make(chan tea.Msg)
+	go func() {
+		defer close(updates)
+		start := time.Now()
+		done := requestDoneMsg{sequence: sequence}
+		if resp, err := req.ExecContext(ctx); err != nil {
+			done.err = err
+		} else {
+			done.resp, done.err = internal.ReadResponse(resp, func(received, total int64) {
+				updates <- requestProgressMsg{sequence, received, total, updates}
+			})
+		}
+		done.duration = time.Since(start)
+		updates <- done
+	}()
+	return waitForUpdate(updates)
+}
+
+// responseBodySavedMsg is the result of saving the response body to a file
+type responseBodySavedMsg struct {
+	path string
+	err  error
+}
+
+// saveResponseBody writes the body of the current response to a file in the background
+func (m *RootModel) saveResponseBody(path string) tea.Cmd {
+	resp := m.rctx.Response()
+	if resp == nil {
+		return nil
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return func() tea.Msg {
+		return responseBodySavedMsg{path: path, err: resp.SaveBody(path)}
+	}
+}
+
+func waitForUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
@@ -1,59 +1,33 @@
 package tui
 
-import "github.com/elliotchance/orderedmap/v2"
+import (
+	"strings"
 
-type Keymap = orderedmap.OrderedMap[string, string]
-
-func NewKeymap() *Keymap {
-	return orderedmap.NewOrderedMap[string, string]()
-}
-
-var (
-	EmptyKeymap              = NewKeymap()
-	CollectionPaneKeymap     = NewKeymap()
-	CollectionListPaneKeymap = NewKeymap()
-	UrlPaneKeymap            = NewKeymap()
-	RequestPaneKeymap        = NewKeymap()
-	ResponsePaneKeymap       = NewKeymap()
-	SelectMethodDialogKeymap = NewKeymap()
-	TextInputDialogKeymap    = NewKeymap()
-	TextAreaDialogKeymap     = NewKeymap()
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/views"
 )
 
-func init() {
-	CollectionPaneKeymap.Set("<enter>", "Select")
-	CollectionPaneKeymap.Set("x", "Execute")
-	CollectionPaneKeymap.Set("n", "New")
-	CollectionPaneKeymap.Set("r", "Rename")
-	CollectionPaneKeymap.Set("d", "Delete")
-	CollectionPaneKeymap.Set("c", "Copy")
-
-	CollectionListPaneKeymap.Set("<enter>", "Select")
-	CollectionListPaneKeymap.Set("n", "New")
-	CollectionListPaneKeymap.Set("r", "Rename")
-	CollectionListPaneKeymap.Set("d", "Delete")
-
-	UrlPaneKeymap.Set("x", "Execute")
-	UrlPaneKeymap.Set("m", "Select method")
-	UrlPaneKeymap.Set("<enter>", "Edit")
-	UrlPaneKeymap.Set("r", "Rename")
-	UrlPaneKeymap.Set("<esc>", "Back")
-
-	RequestPaneKeymap.Set("x", "Execute")
-	RequestPaneKeymap.Set("<enter>", "Edit")
-	RequestPaneKeymap.Set("n", "New")
-	RequestPaneKeymap.Set("d", "Delete")
-	RequestPaneKeymap.Set("<esc>", "Back")
-
-	ResponsePaneKeymap.Set("<esc>", "Back")
-
-	SelectMethodDialogKeymap.Set("<enter>", "Select")
-	SelectMethodDialogKeymap.Set("<esc>", "Cancel")
-
-	TextInputDialogKeymap.Set("<enter>", "Submit")
-	TextInputDialogKeymap.Set("<esc>", "Cancel")
+// viewScopes maps each view to the scope of its key bindings
+var viewScopes = map[views.View]keys.Scope{
+	views.CollectionPaneView:     keys.CollectionScope,
+	views.CollectionListPaneView: keys.CollectionListScope,
+	views.UrlPaneView:            keys.UrlScope,
+	views.RequestPaneView:        keys.RequestScope,
+	views.ResponsePaneView:       keys.ResponseScope,
+	views.SelectMethodDialogView: keys.SelectDialogScope,
+	views.TextInputDialogView:    keys.TextInputDialogScope,
+	views.TextAreaDialogView:     keys.TextAreaDialogScope,
+	views.SelectDialogView:       keys.SelectDialogScope,
+	views.ConfirmDialogView:      keys.ConfirmDialogScope,
+	views.FinderDialogView:       keys.FinderDialogScope,
+	views.HelpDialogView:         keys.HelpDialogScope,
+}
 
-	TextAreaDialogKeymap.Set("<ctrl+w>", "Submit")
-	TextAreaDialogKeymap.Set("<esc>", "Cancel")
-	TextAreaDialogKeymap.Set("<enter>", "New line")
+// renderKeymap renders the effective bindings of a scope for the footer
+func renderKeymap(scope keys.Scope) string {
+	var strs []string
+	for _, item := range keys.Current().Help(scope) {
+		strs = append(strs, item.Description+": "+item.Key)
+	}
+	return strings.Join(strs, " | ")
 }
@@ -0,0 +1,91 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the key binding config file, e.g.
+//
+//	preset: vim
+//	bindings:
+//	  global:
+//	    quit: ctrl+q
+//	  collection:
+//	    delete: [D, delete]
+type Config struct {
+	Preset   string                       `yaml:"preset"`
+	Bindings map[Scope]map[Action]keyList `yaml:"bindings"`
+}
+
+// keyList is a list of keys, written either as a single key or a sequence
+type keyList []string
+
+func (l *keyList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var key string
+		if err := value.Decode(&key); err != nil {
+			return err
+		}
+		*l = keyList{key}
+		return nil
+	}
+	var keys []string
+	if err := value.Decode(&keys); err != nil {
+		return err
+	}
+	*l = keys
+	return nil
+}
+
+// DefaultConfigPath returns the path of the user key binding config
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "agora", "keybindings.yaml"), nil
+}
+
+// Build returns the keymap of the preset with the bindings of the config applied
+func (c Config) Build() (*Keymap, error) {
+	k, err := Preset(c.Preset)
+	if err != nil {
+		return nil, err
+	}
+	for scope, bindings := range c.Bindings {
+		for action, keys := range bindings {
+			if err := k.Bind(scope, action, keys); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// LoadConfig reads the key binding config at path and builds its keymap.
+// If the file does not exist and must is false, the default keymap is returned.
+func LoadConfig(path string, must bool) (*Keymap, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !must {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid key binding config %s: %w", path, err)
+	}
+	k, err := config.Build()
+	if err != nil {
+		return nil, fmt.Errorf("invalid key binding config %s: %w", path, err)
+	}
+	return k, nil
+}
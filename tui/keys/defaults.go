@@ -0,0 +1,427 @@
+package keys
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Default returns the default keymap
+func Default() *Keymap {
+	return &Keymap{scopes: map[Scope][]Binding{
+		GlobalScope: {
+			{Quit, []string{"q", "ctrl+c"}},
+			{Undo, []string{"u"}},
+			{FindRequest, []string{"ctrl+f"}},
+			{CommandPalette, []string{"ctrl+p"}},
+			{ShowHelp, []string{"?"}},
+			{FocusCollection, []string{"1"}},
+			{FocusCollections, []string{"2"}},
+			{FocusUrl, []string{"3"}},
+			{FocusRequest, []string{"4"}},
+			{FocusResponse, []string{"5"}},
+			{ZoomPane, []string{"z"}},
+			{ShrinkSidebar, []string{"<"}},
+			{GrowSidebar, []string{">"}},
+			{ShrinkRequest, []string{"{"}},
+			{GrowRequest, []string{"}"}},
+			{CycleLayout, []string{"|"}},
+			{ResetLayout, []string{"="}},
+		},
+		CollectionScope: {
+			{Select, []string{"enter"}},
+			{Execute, []string{"x"}},
+			{New, []string{"n"}},
+			{Rename, []string{"r"}},
+			{Delete, []string{"d"}},
+			{Duplicate, []string{"c"}},
+			{MoveTo, []string{"m"}},
+			{CopyTo, []string{"C"}},
+			{MoveUp, []string{"K", "shift+up"}},
+			{MoveDown, []string{"J", "shift+down"}},
+			{Copy, []string{"y"}},
+			{CopyRequest, []string{"Y"}},
+			{Search, []string{"/"}},
+			{ClearSearch, []string{"esc"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+		CollectionListScope: {
+			{Select, []string{"enter"}},
+			{New, []string{"n"}},
+			{Rename, []string{"r"}},
+			{Delete, []string{"d"}},
+			{Duplicate, []string{"c"}},
+			{MoveUp, []string{"K", "shift+up"}},
+			{MoveDown, []string{"J", "shift+down"}},
+			{Trash, []string{"t"}},
+			{Search, []string{"/"}},
+			{ClearSearch, []string{"esc"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+		UrlScope: {
+			{Execute, []string{"x"}},
+			{SelectMethod, []string{"m"}},
+			{Edit, []string{"enter"}},
+			{Rename, []string{"r"}},
+			{Copy, []string{"y"}},
+			{CopyRequest, []string{"Y"}},
+			{Back, []string{"esc"}},
+		},
+		RequestScope: {
+			{Execute, []string{"x"}},
+			{Edit, []string{"enter"}},
+			{OpenEditor, []string{"e"}},
+			{BulkEdit, []string{"b"}},
+			{Toggle, []string{" "}},
+			{New, []string{"n"}},
+			{Delete, []string{"d"}},
+			{Copy, []string{"y"}},
+			{CopyRow, []string{"Y"}},
+			{PrevTab, []string{"[", "shift+tab"}},
+			{NextTab, []string{"]", "tab"}},
+			{Back, []string{"esc"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+		ResponseScope: {
+			{PrevTab, []string{"[", "shift+tab"}},
+			{NextTab, []string{"]", "tab"}},
+			{Search, []string{"/"}},
+			{NextMatch, []string{"n"}},
+			{PrevMatch, []string{"N"}},
+			{Filter, []string{"f"}},
+			{ToggleTree, []string{"t"}},
+			{ToggleRaw, []string{"r"}},
+			{SaveBody, []string{"s"}},
+			{Copy, []string{"y"}},
+			{CopyRow, []string{"Y"}},
+			{Back, []string{"esc"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+		FilterScope: {
+			{Submit, []string{"enter"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+			{CursorUp, []string{"up"}},
+			{CursorDown, []string{"down"}},
+		},
+		SearchScope: {
+			{Submit, []string{"enter"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+			{ToggleRegex, []string{"ctrl+r"}},
+			{NextMatch, []string{"down", "ctrl+n"}},
+			{PrevMatch, []string{"up", "ctrl+p"}},
+		},
+		ResponseFilterScope: {
+			{Submit, []string{"enter"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+		},
+		JsonTreeScope: {
+			{ToggleFold, []string{"enter", " "}},
+			{Collapse, []string{"left", "h"}},
+			{Expand, []string{"right", "l"}},
+			{CollapseAll, []string{"-"}},
+			{ExpandAll, []string{"+", "="}},
+			{CopyPath, []string{"y"}},
+			{ToggleTree, []string{"t"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+		SelectDialogScope: {
+			{Select, []string{"enter"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+		TextInputDialogScope: {
+			{Submit, []string{"enter"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+			{CursorUp, []string{"up"}},
+			{CursorDown, []string{"down"}},
+			{Complete, []string{"tab"}},
+			{NextSuggestion, []string{"ctrl+n"}},
+			{PrevSuggestion, []string{"ctrl+p"}},
+		},
+		TextAreaDialogScope: {
+			{Submit, []string{"ctrl+w"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+		},
+		ConfirmDialogScope: {
+			{Confirm, []string{"y", "Y", "enter"}},
+			{Cancel, []string{"n", "N", "esc", "ctrl+c"}},
+		},
+		FinderDialogScope: {
+			{Select, []string{"enter"}},
+			{Cancel, []string{"esc", "ctrl+c"}},
+			{CursorUp, []string{"up", "ctrl+p"}},
+			{CursorDown, []string{"down", "ctrl+n"}},
+		},
+		HelpDialogScope: {
+			{Cancel, []string{"esc", "q", "?"}},
+			{CursorUp, []string{"up", "k"}},
+			{CursorDown, []string{"down", "j"}},
+		},
+	}}
+}
+
+// Preset names
+const (
+	DefaultPreset = "default"
+	VimPreset     = "vim"
+	EmacsPreset   = "emacs"
+)
+
+type override struct {
+	scope  Scope
+	action Action
+	keys   []string
+}
+
+// presets are applied on top of the default keymap
+var presets = map[string][]override{
+	DefaultPreset: nil,
+	VimPreset: {
+		{GlobalScope, CommandPalette, []string{":", "ctrl+p"}},
+		{CollectionScope, New, []string{"o"}},
+		{CollectionScope, Duplicate, []string{"p"}},
+		{CollectionListScope, New, []string{"o"}},
+		{CollectionListScope, Duplicate, []string{"p"}},
+		{UrlScope, Edit, []string{"i", "enter"}},
+		{RequestScope, New, []string{"o"}},
+		{RequestScope, Edit, []string{"i", "enter"}},
+		{RequestScope, PrevTab, []string{"H", "[", "shift+tab"}},
+		{RequestScope, NextTab, []string{"L", "]", "tab"}},
+		{ResponseScope, PrevTab, []string{"H", "[", "shift+tab"}},
+		{ResponseScope, NextTab, []string{"L", "]", "tab"}},
+		{FinderDialogScope, CursorUp, []string{"up", "ctrl+p", "ctrl+k"}},
+		{FinderDialogScope, CursorDown, []string{"down", "ctrl+n", "ctrl+j"}},
+	},
+	EmacsPreset: {
+		{GlobalScope, Quit, []string{"ctrl+c"}},
+		{GlobalScope, Undo, []string{"ctrl+_", "u"}},
+		{GlobalScope, CommandPalette, []string{"alt+x"}},
+		{CollectionScope, Search, []string{"ctrl+s", "/"}},
+		{CollectionScope, ClearSearch, []string{"esc", "ctrl+g"}},
+		{CollectionScope, CursorUp, []string{"up", "ctrl+p"}},
+		{CollectionScope, CursorDown, []string{"down", "ctrl+n"}},
+		{CollectionScope, Copy, []string{"y", "alt+w"}},
+		{CollectionListScope, Search, []string{"ctrl+s", "/"}},
+		{CollectionListScope, ClearSearch, []string{"esc", "ctrl+g"}},
+		{CollectionListScope, CursorUp, []string{"up", "ctrl+p"}},
+		{CollectionListScope, CursorDown, []string{"down", "ctrl+n"}},
+		{UrlScope, Back, []string{"esc", "ctrl+g"}},
+		{UrlScope, Copy, []string{"y", "alt+w"}},
+		{RequestScope, Back, []string{"esc", "ctrl+g"}},
+		{RequestScope, CursorUp, []string{"up", "ctrl+p"}},
+		{RequestScope, CursorDown, []string{"down", "ctrl+n"}},
+		{RequestScope, Copy, []string{"y", "alt+w"}},
+		{ResponseScope, Search, []string{"ctrl+s", "/"}},
+		{ResponseScope, Back, []string{"esc", "ctrl+g"}},
+		{ResponseScope, CursorUp, []string{"up", "ctrl+p"}},
+		{ResponseScope, CursorDown, []string{"down", "ctrl+n"}},
+		{ResponseScope, Copy, []string{"y", "alt+w"}},
+		{FilterScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{FilterScope, CursorUp, []string{"up", "ctrl+p"}},
+		{FilterScope, CursorDown, []string{"down", "ctrl+n"}},
+		{SearchScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{SearchScope, NextMatch, []string{"down", "ctrl+n", "ctrl+s"}},
+		{SearchScope, PrevMatch, []string{"up", "ctrl+p", "ctrl+r"}},
+		{SearchScope, ToggleRegex, []string{"alt+r"}},
+		{ResponseFilterScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{JsonTreeScope, Collapse, []string{"left", "ctrl+b"}},
+		{JsonTreeScope, Expand, []string{"right"}},
+		{JsonTreeScope, CopyPath, []string{"y", "alt+w"}},
+		{JsonTreeScope, CursorUp, []string{"up", "ctrl+p"}},
+		{JsonTreeScope, CursorDown, []string{"down", "ctrl+n"}},
+		{SelectDialogScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{SelectDialogScope, CursorUp, []string{"up", "ctrl+p"}},
+		{SelectDialogScope, CursorDown, []string{"down", "ctrl+n"}},
+		{TextInputDialogScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{TextAreaDialogScope, Submit, []string{"ctrl+s"}},
+		{TextAreaDialogScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{ConfirmDialogScope, Cancel, []string{"n", "N", "esc", "ctrl+g", "ctrl+c"}},
+		{FinderDialogScope, Cancel, []string{"esc", "ctrl+g", "ctrl+c"}},
+		{HelpDialogScope, Cancel, []string{"esc", "ctrl+g", "q", "?"}},
+		{HelpDialogScope, CursorUp, []string{"up", "ctrl+p"}},
+		{HelpDialogScope, CursorDown, []string{"down", "ctrl+n"}},
+	},
+}
+
+// Preset returns the default keymap with a preset applied
+func Preset(name string) (*Keymap, error) {
+	if name == "" {
+		name = DefaultPreset
+	}
+	overrides, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key binding preset: %s", name)
+	}
+	k := Default()
+	for _, o := range overrides {
+		if err := k.Bind(o.scope, o.action, o.keys); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// HelpEntry describes actions in the footer and help.
+// Keys are shown for entries that are not bound to actions.
+type HelpEntry struct {
+	Description string
+	Actions     []Action
+	Keys        []string
+}
+
+// help lists the entries shown for each scope, in order
+var help = map[Scope][]HelpEntry{
+	CollectionScope: {
+		{Description: "Select", Actions: []Action{Select}},
+		{Description: "Execute", Actions: []Action{Execute}},
+		{Description: "New", Actions: []Action{New}},
+		{Description: "Rename", Actions: []Action{Rename}},
+		{Description: "Delete", Actions: []Action{Delete}},
+		{Description: "Duplicate", Actions: []Action{Duplicate}},
+		{Description: "Move to", Actions: []Action{MoveTo}},
+		{Description: "Copy to", Actions: []Action{CopyTo}},
+		{Description: "Move up/down", Actions: []Action{MoveUp, MoveDown}},
+		{Description: "Copy URL/curl", Actions: []Action{Copy, CopyRequest}},
+		{Description: "Undo", Actions: []Action{Undo}},
+		{Description: "Search", Actions: []Action{Search}},
+		{Description: "Find request", Actions: []Action{FindRequest}},
+		{Description: "Commands", Actions: []Action{CommandPalette}},
+		{Description: "Help", Actions: []Action{ShowHelp}},
+	},
+	CollectionListScope: {
+		{Description: "Select", Actions: []Action{Select}},
+		{Description: "New", Actions: []Action{New}},
+		{Description: "Rename", Actions: []Action{Rename}},
+		{Description: "Delete", Actions: []Action{Delete}},
+		{Description: "Duplicate", Actions: []Action{Duplicate}},
+		{Description: "Move up/down", Actions: []Action{MoveUp, MoveDown}},
+		{Description: "Trash", Actions: []Action{Trash}},
+		{Description: "Undo", Actions: []Action{Undo}},
+		{Description: "Search", Actions: []Action{Search}},
+		{Description: "Find request", Actions: []Action{FindRequest}},
+		{Description: "Commands", Actions: []Action{CommandPalette}},
+		{Description: "Help", Actions: []Action{ShowHelp}},
+	},
+	UrlScope: {
+		{Description: "Execute", Actions: []Action{Execute}},
+		{Description: "Select method", Actions: []Action{SelectMethod}},
+		{Description: "Edit", Actions: []Action{Edit}},
+		{Description: "Rename", Actions: []Action{Rename}},
+		{Description: "Copy URL/curl", Actions: []Action{Copy, CopyRequest}},
+		{Description: "Back", Actions: []Action{Back}},
+		{Description: "Help", Actions: []Action{ShowHelp}},
+	},
+	RequestScope: {
+		{Description: "Execute", Actions: []Action{Execute}},
+		{Description: "Edit", Actions: []Action{Edit}},
+		{Description: "$EDITOR", Actions: []Action{OpenEditor}},
+		{Description: "Bulk edit", Actions: []Action{BulkEdit}},
+		{Description: "Enable/disable", Actions: []Action{Toggle}},
+		{Description: "New", Actions: []Action{New}},
+		{Description: "Delete", Actions: []Action{Delete}},
+		{Description: "Copy value/row", Actions: []Action{Copy, CopyRow}},
+		{Description: "Undo", Actions: []Action{Undo}},
+		{Description: "Back", Actions: []Action{Back}},
+		{Description: "Help", Actions: []Action{ShowHelp}},
+	},
+	ResponseScope: {
+		{Description: "Search", Actions: []Action{Search}},
+		{Description: "Next/prev match", Actions: []Action{NextMatch, PrevMatch}},
+		{Description: "Filter", Actions: []Action{Filter}},
+		{Description: "Tree", Actions: []Action{ToggleTree}},
+		{Description: "Raw", Actions: []Action{ToggleRaw}},
+		{Description: "Save", Actions: []Action{SaveBody}},
+		{Description: "Copy", Actions: []Action{Copy, CopyRow}},
+		{Description: "Back", Actions: []Action{Back}},
+		{Description: "Help", Actions: []Action{ShowHelp}},
+	},
+	FilterScope: {
+		{Description: "Apply", Actions: []Action{Submit}},
+		{Description: "Navigate", Actions: []Action{CursorUp, CursorDown}},
+		{Description: "Clear", Actions: []Action{Cancel}},
+	},
+	SearchScope: {
+		{Description: "Apply", Actions: []Action{Submit}},
+		{Description: "Next/prev match", Actions: []Action{NextMatch, PrevMatch}},
+		{Description: "Regex", Actions: []Action{ToggleRegex}},
+		{Description: "Clear", Actions: []Action{Cancel}},
+	},
+	ResponseFilterScope: {
+		{Description: "Apply", Actions: []Action{Submit}},
+		{Description: "Cancel", Actions: []Action{Cancel}},
+	},
+	JsonTreeScope: {
+		{Description: "Fold", Actions: []Action{ToggleFold}},
+		{Description: "Collapse/expand", Actions: []Action{Collapse, Expand}},
+		{Description: "All", Actions: []Action{CollapseAll, ExpandAll}},
+		{Description: "Copy path", Actions: []Action{CopyPath}},
+		{Description: "Text view", Actions: []Action{ToggleTree}},
+	},
+	SelectDialogScope: {
+		{Description: "Select", Actions: []Action{Select}},
+		{Description: "Cancel", Actions: []Action{Cancel}},
+	},
+	TextInputDialogScope: {
+		{Description: "Submit", Actions: []Action{Submit}},
+		{Description: "Cancel", Actions: []Action{Cancel}},
+		{Description: "Complete", Actions: []Action{Complete}},
+	},
+	TextAreaDialogScope: {
+		{Description: "Submit", Actions: []Action{Submit}},
+		{Description: "Cancel", Actions: []Action{Cancel}},
+		{Description: "New line", Keys: []string{"enter"}},
+	},
+	ConfirmDialogScope: {
+		{Description: "Confirm", Actions: []Action{Confirm}},
+		{Description: "Cancel", Actions: []Action{Cancel}},
+	},
+	FinderDialogScope: {
+		{Description: "Select", Actions: []Action{Select}},
+		{Description: "Navigate", Actions: []Action{CursorUp, CursorDown}},
+		{Description: "Cancel", Actions: []Action{Cancel}},
+	},
+	HelpDialogScope: {
+		{Description: "Scroll", Actions: []Action{CursorUp, CursorDown}},
+		{Description: "Page", Keys: []string{"pgup", "pgdown"}},
+		{Description: "Close", Actions: []Action{Cancel}},
+	},
+}
+
+// HelpItem is a help entry with the effective keys
+type HelpItem struct {
+	Description string
+	Key         string
+}
+
+// Help returns the help of a scope with the keys of this keymap.
+// Entries with unbound actions are left out.
+func (k *Keymap) Help(scope Scope) []HelpItem {
+	var items []HelpItem
+	for _, entry := range help[scope] {
+		keys := entry.Keys
+		if len(entry.Actions) > 0 {
+			keys = nil
+			for _, action := range entry.Actions {
+				bound := k.Keys(scope, action)
+				if len(bound) == 0 {
+					break
+				}
+				keys = append(keys, bound[0])
+			}
+			if len(keys) != len(entry.Actions) {
+				continue
+			}
+		}
+		formatted := make([]string, len(keys))
+		for i, key := range keys {
+			formatted[i] = FormatKey(key)
+		}
+		items = append(items, HelpItem{Description: entry.Description, Key: strings.Join(formatted, "/")})
+	}
+	return items
+}
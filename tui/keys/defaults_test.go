@@ -0,0 +1,13 @@
+package keys
+
+import "testing"
+
+func TestPresetsAreValid(t *testing.T) {
+	for name := range presets {
+		t.Run(name, func(t *testing.T) {
+			if _, err := (Config{Preset: name}).Build(); err != nil {
+				t.Errorf("preset %s: %v", name, err)
+			}
+		})
+	}
+}
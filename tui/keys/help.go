@@ -0,0 +1,134 @@
+package keys
+
+import "strings"
+
+// helpSections are the scopes listed by the full help, in order
+var helpSections = []struct {
+	scope Scope
+	title string
+}{
+	{GlobalScope, "Global"},
+	{CollectionScope, "Collection pane"},
+	{CollectionListScope, "Collections pane"},
+	{UrlScope, "URL pane"},
+	{RequestScope, "Request pane"},
+	{ResponseScope, "Response pane"},
+	{FilterScope, "Search in collection panes"},
+	{SearchScope, "Search in response"},
+	{ResponseFilterScope, "Response filter"},
+	{JsonTreeScope, "JSON tree"},
+	{SelectDialogScope, "Select dialog"},
+	{TextInputDialogScope, "Text input dialog"},
+	{TextAreaDialogScope, "Text area dialog"},
+	{ConfirmDialogScope, "Confirm dialog"},
+	{FinderDialogScope, "Finder and command palette"},
+	{HelpDialogScope, "Help"},
+}
+
+var actionDescriptions = map[Action]string{
+	Select:           "Select",
+	Execute:          "Execute request",
+	New:              "New",
+	Rename:           "Rename",
+	Delete:           "Delete",
+	Duplicate:        "Duplicate",
+	MoveTo:           "Move to collection",
+	CopyTo:           "Copy to collection",
+	MoveUp:           "Move up",
+	MoveDown:         "Move down",
+	Search:           "Search",
+	ClearSearch:      "Clear search",
+	NextMatch:        "Next match",
+	PrevMatch:        "Previous match",
+	ToggleRegex:      "Toggle regex",
+	Filter:           "Filter JSON with jq",
+	ToggleTree:       "Toggle tree view",
+	ToggleRaw:        "Toggle raw/pretty body",
+	SaveBody:         "Save body to file",
+	ToggleFold:       "Fold/unfold",
+	Collapse:         "Collapse",
+	Expand:           "Expand",
+	CollapseAll:      "Collapse all",
+	ExpandAll:        "Expand all",
+	CopyPath:         "Copy JSON path",
+	Copy:             "Copy URL, value or body",
+	CopyRow:          "Copy row",
+	CopyRequest:      "Copy request as curl",
+	Edit:             "Edit",
+	OpenEditor:       "Edit in $EDITOR",
+	BulkEdit:         "Edit all params or headers as text",
+	Toggle:           "Enable/disable param or header",
+	Complete:         "Complete suggestion",
+	NextSuggestion:   "Next suggestion",
+	PrevSuggestion:   "Previous suggestion",
+	SelectMethod:     "Select method",
+	Back:             "Back to collection",
+	PrevTab:          "Previous tab",
+	NextTab:          "Next tab",
+	Trash:            "Show trash",
+	CursorUp:         "Up",
+	CursorDown:       "Down",
+	Submit:           "Submit",
+	Cancel:           "Cancel",
+	Confirm:          "Confirm",
+	Undo:             "Undo",
+	FindRequest:      "Find request",
+	CommandPalette:   "Command palette",
+	ShowHelp:         "Help",
+	Quit:             "Quit",
+	FocusCollection:  "Focus Collection pane",
+	FocusCollections: "Focus Collections pane",
+	FocusUrl:         "Focus URL pane",
+	FocusRequest:     "Focus Request pane",
+	FocusResponse:    "Focus Response pane",
+	ZoomPane:         "Maximize/restore the focused pane",
+	ShrinkSidebar:    "Shrink the collection panes",
+	GrowSidebar:      "Grow the collection panes",
+	ShrinkRequest:    "Shrink the request pane",
+	GrowRequest:      "Grow the request pane",
+	CycleLayout:      "Switch layout (auto, side by side, stacked)",
+	ResetLayout:      "Reset layout",
+}
+
+// HelpSection lists the bindings of a scope
+type HelpSection struct {
+	Title string
+	Items []HelpItem
+}
+
+// formatKeys formats keys for display, separated by spaces
+func formatKeys(keys []string) string {
+	formatted := make([]string, len(keys))
+	for i, key := range keys {
+		formatted[i] = FormatKey(key)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// FullHelp returns every binding of this keymap grouped by scope,
+// followed by the keys of each scope that are not bound to actions.
+func (k *Keymap) FullHelp() []HelpSection {
+	var sections []HelpSection
+	for _, s := range helpSections {
+		section := HelpSection{Title: s.title}
+		for _, b := range k.scopes[s.scope] {
+			if len(b.Keys) == 0 {
+				continue
+			}
+			section.Items = append(section.Items, HelpItem{
+				Description: actionDescriptions[b.Action],
+				Key:         formatKeys(b.Keys),
+			})
+		}
+		for _, entry := range help[s.scope] {
+			if len(entry.Actions) == 0 {
+				section.Items = append(section.Items, HelpItem{
+					Description: entry.Description,
+					Key:         formatKeys(entry.Keys),
+				})
+			}
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
@@ -0,0 +1,242 @@
+// Key bindings of the panes and dialogs.
+// Keys are bound to named actions per scope, so that panes
+// dispatch on actions instead of hard-coded keys.
+package keys
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action is a named operation that keys can be bound to
+type Action string
+
+const (
+	Select           Action = "select"
+	Execute          Action = "execute"
+	New              Action = "new"
+	Rename           Action = "rename"
+	Delete           Action = "delete"
+	Duplicate        Action = "duplicate"
+	MoveTo           Action = "move_to"
+	CopyTo           Action = "copy_to"
+	MoveUp           Action = "move_up"
+	MoveDown         Action = "move_down"
+	Search           Action = "search"
+	ClearSearch      Action = "clear_search"
+	NextMatch        Action = "next_match"
+	PrevMatch        Action = "prev_match"
+	ToggleRegex      Action = "toggle_regex"
+	Filter           Action = "filter"
+	ToggleTree       Action = "toggle_tree"
+	ToggleRaw        Action = "toggle_raw"
+	SaveBody         Action = "save_body"
+	ToggleFold       Action = "toggle_fold"
+	Collapse         Action = "collapse"
+	Expand           Action = "expand"
+	CollapseAll      Action = "collapse_all"
+	ExpandAll        Action = "expand_all"
+	CopyPath         Action = "copy_path"
+	Copy             Action = "copy"
+	CopyRow          Action = "copy_row"
+	CopyRequest      Action = "copy_request"
+	Edit             Action = "edit"
+	OpenEditor       Action = "open_editor"
+	BulkEdit         Action = "bulk_edit"
+	Toggle           Action = "toggle"
+	Complete         Action = "complete"
+	NextSuggestion   Action = "next_suggestion"
+	PrevSuggestion   Action = "prev_suggestion"
+	SelectMethod     Action = "select_method"
+	Back             Action = "back"
+	PrevTab          Action = "prev_tab"
+	NextTab          Action = "next_tab"
+	Trash            Action = "trash"
+	CursorUp         Action = "cursor_up"
+	CursorDown       Action = "cursor_down"
+	Submit           Action = "submit"
+	Cancel           Action = "cancel"
+	Confirm          Action = "confirm"
+	Undo             Action = "undo"
+	FindRequest      Action = "find_request"
+	CommandPalette   Action = "command_palette"
+	ShowHelp         Action = "help"
+	Quit             Action = "quit"
+	FocusCollection  Action = "focus_collection"
+	FocusCollections Action = "focus_collections"
+	FocusUrl         Action = "focus_url"
+	FocusRequest     Action = "focus_request"
+	FocusResponse    Action = "focus_response"
+	ZoomPane         Action = "zoom_pane"
+	ShrinkSidebar    Action = "shrink_sidebar"
+	GrowSidebar      Action = "grow_sidebar"
+	ShrinkRequest    Action = "shrink_request"
+	GrowRequest      Action = "grow_request"
+	CycleLayout      Action = "cycle_layout"
+	ResetLayout      Action = "reset_layout"
+)
+
+// Scope is a pane or dialog that has its own bindings.
+// Bindings of the global scope apply everywhere unless
+// the key is bound in the focused scope.
+type Scope string
+
+const (
+	GlobalScope          Scope = "global"
+	CollectionScope      Scope = "collection"
+	CollectionListScope  Scope = "collections"
+	UrlScope             Scope = "url"
+	RequestScope         Scope = "request"
+	ResponseScope        Scope = "response"
+	FilterScope          Scope = "filter"
+	SearchScope          Scope = "search"
+	ResponseFilterScope  Scope = "response_filter"
+	JsonTreeScope        Scope = "json_tree"
+	SelectDialogScope    Scope = "select_dialog"
+	TextInputDialogScope Scope = "text_input_dialog"
+	TextAreaDialogScope  Scope = "text_area_dialog"
+	ConfirmDialogScope   Scope = "confirm_dialog"
+	FinderDialogScope    Scope = "finder_dialog"
+	HelpDialogScope      Scope = "help_dialog"
+)
+
+// Binding binds keys to an action.
+// Keys are written as printed by tea.KeyMsg.String, e.g. "x", "enter", "ctrl+p".
+type Binding struct {
+	Action Action
+	Keys   []string
+}
+
+// ActionMsg runs an action in the focused pane as if its key was pressed
+type ActionMsg struct {
+	Action Action
+}
+
+// Keymap holds the bindings of every scope
+type Keymap struct {
+	scopes map[Scope][]Binding
+}
+
+// Clone returns a deep copy of the keymap
+func (k *Keymap) Clone() *Keymap {
+	c := &Keymap{scopes: make(map[Scope][]Binding, len(k.scopes))}
+	for scope, bindings := range k.scopes {
+		cloned := make([]Binding, len(bindings))
+		for i, b := range bindings {
+			cloned[i] = Binding{Action: b.Action, Keys: slices.Clone(b.Keys)}
+		}
+		c.scopes[scope] = cloned
+	}
+	return c
+}
+
+// Bind replaces the keys of an action in a scope.
+// The action must be one of the actions of the scope.
+func (k *Keymap) Bind(scope Scope, action Action, keys []string) error {
+	bindings, ok := k.scopes[scope]
+	if !ok {
+		return fmt.Errorf("unknown key binding scope: %s", scope)
+	}
+	for i := range bindings {
+		if bindings[i].Action == action {
+			bindings[i].Keys = normalizeKeys(keys)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %s in key binding scope %s", action, scope)
+}
+
+// validate makes sure no key is bound to two actions of the same scope
+func (k *Keymap) validate() error {
+	for scope, bindings := range k.scopes {
+		seen := make(map[string]Action)
+		for _, b := range bindings {
+			for _, key := range b.Keys {
+				if other, ok := seen[key]; ok && other != b.Action {
+					return fmt.Errorf("key %q is bound to both %s and %s in %s", key, other, b.Action, scope)
+				}
+				seen[key] = b.Action
+			}
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys bound to an action in a scope,
+// falling back to the global scope.
+func (k *Keymap) Keys(scope Scope, action Action) []string {
+	for _, s := range []Scope{scope, GlobalScope} {
+		for _, b := range k.scopes[s] {
+			if b.Action == action {
+				return b.Keys
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the action of a key or action message in a scope,
+// or "" if msg does not trigger any action.
+func (k *Keymap) Lookup(scope Scope, msg tea.Msg) Action {
+	switch msg := msg.(type) {
+	case ActionMsg:
+		return msg.Action
+	case tea.KeyMsg:
+		key := msg.String()
+		for _, b := range k.scopes[scope] {
+			if slices.Contains(b.Keys, key) {
+				return b.Action
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeKeys accepts "space" as an alias of " "
+func normalizeKeys(keys []string) []string {
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.EqualFold(key, "space") {
+			key = " "
+		}
+		normalized = append(normalized, key)
+	}
+	return normalized
+}
+
+// FormatKey formats a key for display, e.g. "x" or "<ctrl+p>"
+func FormatKey(key string) string {
+	switch {
+	case key == " ":
+		return "<space>"
+	case len([]rune(key)) == 1:
+		return key
+	default:
+		return "<" + key + ">"
+	}
+}
+
+var current = Default()
+
+// Current returns the keymap in effect
+func Current() *Keymap {
+	return current
+}
+
+// SetCurrent sets the keymap in effect
+func SetCurrent(k *Keymap) {
+	current = k
+}
+
+// Lookup returns the action of msg in a scope of the current keymap
+func Lookup(scope Scope, msg tea.Msg) Action {
+	return current.Lookup(scope, msg)
+}
+
+// Keys returns the keys bound to an action in the current keymap
+func Keys(scope Scope, action Action) []string {
+	return current.Keys(scope, action)
+}
@@ -0,0 +1,315 @@
+package tui
+
+import (
+	"encoding/json"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gabrielfu/agora/internal"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/views"
+)
+
+type layoutMode string
+
+const (
+	// side by side, or stacked if the window is too narrow
+	autoLayout layoutMode = "auto"
+	// the collection panes on the left of the request panes
+	sideBySideLayout layoutMode = "side_by_side"
+	// the collection panes above the request panes
+	stackedLayout layoutMode = "stacked"
+)
+
+var layoutModes = []layoutMode{autoLayout, sideBySideLayout, stackedLayout}
+
+var layoutModeNames = map[layoutMode]string{
+	autoLayout:       "auto",
+	sideBySideLayout: "side by side",
+	stackedLayout:    "stacked",
+}
+
+// Layout is the arrangement of the panes chosen by the user,
+// which is saved in the workspace
+type Layout struct {
+	Mode layoutMode `json:"mode"`
+	// Sidebar is the share of the collection panes, in the width of the
+	// window side by side, and in its height when stacked
+	Sidebar float64 `json:"sidebar"`
+	// Request is the share of the request pane in the height
+	// of the request and response panes
+	Request float64 `json:"request"`
+}
+
+// the layout is saved in this setting of the workspace, as JSON
+const layoutSetting = "layout"
+
+// resizing steps of the keys, as a share of the window
+const layoutStep = 0.05
+
+// windows narrower than this are stacked in the auto layout
+const autoStackedWidth = 80
+
+// minimum sizes of the panes, borders included
+const (
+	minSidebarWidth      = 22
+	minMainWidth         = 42
+	minPaneHeight        = 5
+	minCollectionsHeight = 8
+	minStackedWidth      = 40
+	minZoomWidth         = 20
+)
+
+// the URL pane has a single line
+const urlPaneHeight = 3
+
+func defaultLayout() Layout {
+	return Layout{Mode: autoLayout, Sidebar: 0.33, Request: 0.5}
+}
+
+// clampShare keeps a share of the window within reason, rounded to a
+// hundredth. The panes are then kept above their minimum sizes.
+func clampShare(share float64) float64 {
+	share = math.Round(share*100) / 100
+	return math.Max(layoutStep, math.Min(1-layoutStep, share))
+}
+
+// loadLayout reads the layout saved in the workspace, or returns fallback
+func loadLayout(s internal.SettingsStorage, fallback Layout) Layout {
+	value, err := s.GetSetting(layoutSetting)
+	if err != nil || value == "" {
+		return fallback
+	}
+	layout := fallback
+	if err := json.Unmarshal([]byte(value), &layout); err != nil {
+		return fallback
+	}
+	if _, ok := layoutModeNames[layout.Mode]; !ok {
+		layout.Mode = fallback.Mode
+	}
+	layout.Sidebar = clampShare(layout.Sidebar)
+	layout.Request = clampShare(layout.Request)
+	return layout
+}
+
+func saveLayout(s internal.SettingsStorage, layout Layout) error {
+	data, err := json.Marshal(layout)
+	if err != nil {
+		return err
+	}
+	return s.SetSetting(layoutSetting, string(data))
+}
+
+// paneRect is the position and the size of a pane in the window, borders included
+type paneRect struct {
+	x, y, width, height int
+}
+
+func (r paneRect) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.width && y >= r.y && y < r.y+r.height
+}
+
+// geometry is the position of every pane, as rendered
+type geometry struct {
+	mode        layoutMode
+	collection  paneRect
+	collections paneRect
+	url         paneRect
+	request     paneRect
+	response    paneRect
+}
+
+// split divides size in two parts of at least minSize, the first of about share
+func split(size int, share float64, minSize int) int {
+	first := int(math.Round(float64(size) * share))
+	return max(minSize, min(first, size-minSize))
+}
+
+// sideBySideGeometry puts the collection panes on the left
+// of a window of width by height cells
+func sideBySideGeometry(width, height int, layout Layout) (geometry, bool) {
+	if width < minSidebarWidth+minMainWidth || height < max(minPaneHeight+minCollectionsHeight, urlPaneHeight+2*minPaneHeight) {
+		return geometry{}, false
+	}
+	sidebar := int(math.Round(float64(width) * layout.Sidebar))
+	sidebar = max(minSidebarWidth, min(sidebar, width-minMainWidth))
+	main := width - sidebar
+	rest := height - urlPaneHeight
+	request := split(rest, layout.Request, minPaneHeight)
+	return geometry{
+		mode:        sideBySideLayout,
+		collection:  paneRect{0, 0, sidebar, height - minCollectionsHeight},
+		collections: paneRect{0, height - minCollectionsHeight, sidebar, minCollectionsHeight},
+		url:         paneRect{sidebar, 0, main, urlPaneHeight},
+		request:     paneRect{sidebar, urlPaneHeight, main, request},
+		response:    paneRect{sidebar, urlPaneHeight + request, main, rest - request},
+	}, true
+}
+
+// stackedGeometry puts the collection panes above the others,
+// for windows too narrow to have them side by side
+func stackedGeometry(width, height int, layout Layout) (geometry, bool) {
+	if width < minStackedWidth || height < urlPaneHeight+3*minPaneHeight {
+		return geometry{}, false
+	}
+	top := int(math.Round(float64(height) * layout.Sidebar))
+	top = max(minPaneHeight, min(top, height-urlPaneHeight-2*minPaneHeight))
+	collections := width / 3
+	rest := height - top - urlPaneHeight
+	request := split(rest, layout.Request, minPaneHeight)
+	return geometry{
+		mode:        stackedLayout,
+		collection:  paneRect{0, 0, width - collections, top},
+		collections: paneRect{width - collections, 0, collections, top},
+		url:         paneRect{0, top, width, urlPaneHeight},
+		request:     paneRect{0, top + urlPaneHeight, width, request},
+		response:    paneRect{0, top + urlPaneHeight + request, width, rest - request},
+	}, true
+}
+
+// computeGeometry arranges the panes in a window of width by height cells,
+// in the mode of the layout if they fit. It reports false if they do not
+// fit in any mode.
+func computeGeometry(width, height int, layout Layout) (geometry, bool) {
+	side, sideOk := sideBySideGeometry(width, height, layout)
+	stacked, stackedOk := stackedGeometry(width, height, layout)
+	switch {
+	case layout.Mode == stackedLayout && stackedOk:
+		return stacked, true
+	case layout.Mode == autoLayout && width < autoStackedWidth && stackedOk:
+		return stacked, true
+	case sideOk:
+		return side, true
+	}
+	return stacked, stackedOk
+}
+
+// rect returns the position of a pane
+func (g geometry) rect(v views.View) paneRect {
+	switch v {
+	case views.CollectionPaneView:
+		return g.collection
+	case views.CollectionListPaneView:
+		return g.collections
+	case views.UrlPaneView:
+		return g.url
+	case views.RequestPaneView:
+		return g.request
+	default:
+		return g.response
+	}
+}
+
+// paneAt returns the pane at a position of the window
+func (g geometry) paneAt(x, y int) (views.View, bool) {
+	for _, v := range []views.View{
+		views.CollectionPaneView,
+		views.CollectionListPaneView,
+		views.UrlPaneView,
+		views.RequestPaneView,
+		views.ResponsePaneView,
+	} {
+		if g.rect(v).contains(x, y) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// divider is a border between panes that is dragged to resize them
+type divider int
+
+const (
+	noDivider divider = iota
+	// between the collection panes and the others
+	sidebarDivider
+	// between the request and the response panes
+	requestDivider
+)
+
+// dividerAt returns the divider on the borders at a position of the window
+func (g geometry) dividerAt(x, y int) divider {
+	if g.mode == stackedLayout {
+		if y == g.url.y-1 || y == g.url.y {
+			return sidebarDivider
+		}
+	} else if x == g.url.x-1 || x == g.url.x {
+		return sidebarDivider
+	}
+	if x >= g.request.x && (y == g.response.y-1 || y == g.response.y) {
+		return requestDivider
+	}
+	return noDivider
+}
+
+// dragDivider moves a divider to a position of the window
+func (g geometry) dragDivider(layout Layout, d divider, x, y, width, height int) Layout {
+	switch d {
+	case sidebarDivider:
+		if g.mode == stackedLayout {
+			layout.Sidebar = float64(y+1) / float64(height)
+		} else {
+			layout.Sidebar = float64(x+1) / float64(width)
+		}
+		layout.Sidebar = clampShare(layout.Sidebar)
+	case requestDivider:
+		rest := g.request.height + g.response.height
+		layout.Request = clampShare(float64(y+1-g.request.y) / float64(rest))
+	}
+	return layout
+}
+
+// zoomGeometry gives the whole window to a pane, except the URL pane
+// which keeps its height
+func zoomGeometry(g geometry, width, height int, v views.View) (geometry, bool) {
+	if width < minZoomWidth || height < minPaneHeight {
+		return g, false
+	}
+	full := paneRect{0, 0, width, height}
+	switch v {
+	case views.CollectionPaneView:
+		g.collection = full
+	case views.CollectionListPaneView:
+		g.collections = full
+	case views.UrlPaneView:
+		g.url = paneRect{0, 0, width, urlPaneHeight}
+	case views.RequestPaneView:
+		g.request = full
+	case views.ResponsePaneView:
+		g.response = full
+	}
+	return g, true
+}
+
+// update applies a layout action
+func (l Layout) update(action keys.Action) Layout {
+	switch action {
+	case keys.ShrinkSidebar:
+		l.Sidebar = clampShare(l.Sidebar - layoutStep)
+	case keys.GrowSidebar:
+		l.Sidebar = clampShare(l.Sidebar + layoutStep)
+	case keys.ShrinkRequest:
+		l.Request = clampShare(l.Request - layoutStep)
+	case keys.GrowRequest:
+		l.Request = clampShare(l.Request + layoutStep)
+	case keys.CycleLayout:
+		for i, mode := range layoutModes {
+			if mode == l.Mode {
+				l.Mode = layoutModes[(i+1)%len(layoutModes)]
+				break
+			}
+		}
+	case keys.ResetLayout:
+		l = defaultLayout()
+	}
+	return l
+}
+
+// layoutMsg runs a layout action from the command palette
+type layoutMsg struct {
+	action keys.Action
+}
+
+func layoutCmd(action keys.Action) tea.Cmd {
+	return func() tea.Msg { return layoutMsg{action: action} }
+}
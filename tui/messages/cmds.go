@@ -17,6 +17,10 @@ var (
 	UpdateRequestCmd          = func(f func(*internal.Request)) tea.Cmd {
 		return func() tea.Msg { return UpdateRequestMsg{Func: f} }
 	}
+	// UpdateRequestWithUndoCmd updates the request and allows undoing the update
+	UpdateRequestWithUndoCmd = func(description string, f func(*internal.Request)) tea.Cmd {
+		return func() tea.Msg { return UpdateRequestMsg{Func: f, UndoDescription: description} }
+	}
 	CreateRequestCmd = func(r internal.Request) tea.Cmd {
 		return func() tea.Msg { return CreateRequestMsg{Req: r} }
 	}
@@ -26,6 +30,15 @@ var (
 	CopyRequestCmd = func(r internal.Request) tea.Cmd {
 		return func() tea.Msg { return CopyRequestMsg{Req: r} }
 	}
+	MoveRequestToCollectionCmd = func(r internal.Request, c string) tea.Cmd {
+		return func() tea.Msg { return MoveRequestToCollectionMsg{Req: r, Collection: c} }
+	}
+	CopyRequestToCollectionCmd = func(r internal.Request, c string) tea.Cmd {
+		return func() tea.Msg { return CopyRequestToCollectionMsg{Req: r, Collection: c} }
+	}
+	ReorderRequestCmd = func(id string, index int) tea.Cmd {
+		return func() tea.Msg { return ReorderRequestMsg{ID: id, Index: index} }
+	}
 	SetCollectionCmd = func(c string) tea.Cmd {
 		return func() tea.Msg { return SetCollectionMsg{Collection: c} }
 	}
@@ -35,7 +48,37 @@ var (
 	DeleteCollectionCmd = func(c string) tea.Cmd {
 		return func() tea.Msg { return DeleteCollectionMsg{Collection: c} }
 	}
-	UpdateCollectionCmd = func(old, new string) tea.Cmd {
+	DuplicateCollectionCmd = func(src, dst string) tea.Cmd {
+		return func() tea.Msg { return DuplicateCollectionMsg{Src: src, Dst: dst} }
+	}
+	ReorderCollectionCmd = func(c string, index int) tea.Cmd {
+		return func() tea.Msg { return ReorderCollectionMsg{Collection: c, Index: index} }
+	}
+	ShowTrashCmd        tea.Cmd = func() tea.Msg { return ShowTrashMsg{} }
+	RestoreFromTrashCmd         = func(id string) tea.Cmd {
+		return func() tea.Msg { return RestoreFromTrashMsg{ID: id} }
+	}
+	UndoCmd             tea.Cmd = func() tea.Msg { return UndoMsg{} }
+	UpdateCollectionCmd         = func(old, new string) tea.Cmd {
 		return func() tea.Msg { return UpdateCollectionMsg{OldName: old, NewName: new} }
 	}
+	ShowRequestFinderCmd      tea.Cmd = func() tea.Msg { return ShowRequestFinderMsg{} }
+	ShowCommandPaletteCmd     tea.Cmd = func() tea.Msg { return ShowCommandPaletteMsg{} }
+	ShowCollectionSwitcherCmd tea.Cmd = func() tea.Msg { return ShowCollectionSwitcherMsg{} }
+	ShowHelpCmd               tea.Cmd = func() tea.Msg { return ShowHelpMsg{} }
+	SaveResponseBodyCmd               = func(path string) tea.Cmd {
+		return func() tea.Msg { return SaveResponseBodyMsg{Path: path} }
+	}
+	CopyToClipboardCmd = func(description, text string) tea.Cmd {
+		return func() tea.Msg { return CopyToClipboardMsg{Description: description, Text: text} }
+	}
+	ShowNoticeCmd = func(text string, isError bool) tea.Cmd {
+		return func() tea.Msg { return ShowNoticeMsg{Text: text, Error: isError} }
+	}
+	AddCustomMethodCmd = func(method string) tea.Cmd {
+		return func() tea.Msg { return AddCustomMethodMsg{Method: method} }
+	}
+	JumpToRequestCmd = func(collection, id string) tea.Cmd {
+		return func() tea.Msg { return JumpToRequestMsg{Collection: collection, ID: id} }
+	}
 )
@@ -17,6 +17,8 @@ type ExecuteRequestMsg struct{}
 
 type UpdateRequestMsg struct {
 	Func func(*internal.Request)
+	// if not empty, the update can be undone
+	UndoDescription string
 }
 
 type CreateRequestMsg struct {
@@ -31,6 +33,21 @@ type CopyRequestMsg struct {
 	Req internal.Request
 }
 
+type MoveRequestToCollectionMsg struct {
+	Req        internal.Request
+	Collection string
+}
+
+type CopyRequestToCollectionMsg struct {
+	Req        internal.Request
+	Collection string
+}
+
+type ReorderRequestMsg struct {
+	ID    string
+	Index int
+}
+
 type SetCollectionMsg struct {
 	Collection string
 }
@@ -43,6 +60,58 @@ type DeleteCollectionMsg struct {
 	Collection string
 }
 
+type DuplicateCollectionMsg struct {
+	Src string
+	Dst string
+}
+
+type ReorderCollectionMsg struct {
+	Collection string
+	Index      int
+}
+
+type ShowTrashMsg struct{}
+
+type RestoreFromTrashMsg struct {
+	ID string
+}
+
+type UndoMsg struct{}
+
+type JumpToRequestMsg struct {
+	Collection string
+	ID         string
+}
+
+type ShowRequestFinderMsg struct{}
+
+type ShowCommandPaletteMsg struct{}
+
+type ShowCollectionSwitcherMsg struct{}
+
+type ShowHelpMsg struct{}
+
+type SaveResponseBodyMsg struct {
+	Path string
+}
+
+type CopyToClipboardMsg struct {
+	// Description of what is copied, e.g. "URL"
+	Description string
+	Text        string
+}
+
+// AddCustomMethodMsg remembers a method typed by the user in the workspace
+type AddCustomMethodMsg struct {
+	Method string
+}
+
+// ShowNoticeMsg shows a short notice in the navigation bar
+type ShowNoticeMsg struct {
+	Text  string
+	Error bool
+}
+
 type UpdateCollectionMsg struct {
 	OldName string
 	NewName string
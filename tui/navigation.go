@@ -1,56 +1,69 @@
 package tui
 
 import (
-	"strings"
-
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
 )
 
 type NagivationModel struct {
-	content string
-	focus   views.View
+	width      int
+	content    string
+	focus      views.View
+	inputScope keys.Scope
+
+	// shown instead of the keys until the next key press
+	notice      string
+	noticeError bool
 }
 
 func (m *NagivationModel) SetContent(content string) {
 	m.content = content
 }
 
+// SetWidth sets the width the keys are truncated to
+func (m *NagivationModel) SetWidth(width int) {
+	m.width = width
+}
+
 func (m *NagivationModel) SetFocus(focus views.View) {
 	m.focus = focus
 	m.updateNagivationContent()
 }
 
-func (m *NagivationModel) renderKeymap(keymap *Keymap) string {
-	var strs []string
-	for el := keymap.Front(); el != nil; el = el.Next() {
-		strs = append(strs, el.Value+": "+el.Key)
+// SetInputScope shows the keys of an input typed in the focused pane,
+// such as a filter, instead of the keys of the pane. An empty scope
+// shows the keys of the pane.
+func (m *NagivationModel) SetInputScope(scope keys.Scope) {
+	if m.inputScope != scope {
+		m.inputScope = scope
+		m.updateNagivationContent()
 	}
-	return strings.Join(strs, " | ")
 }
 
 func (m *NagivationModel) updateNagivationContent() {
-	var keymap *Keymap = EmptyKeymap
-	switch m.focus {
-	case views.CollectionPaneView:
-		keymap = CollectionPaneKeymap
-	case views.CollectionListPaneView:
-		keymap = CollectionListPaneKeymap
-	case views.UrlPaneView:
-		keymap = UrlPaneKeymap
-	case views.RequestPaneView:
-		keymap = RequestPaneKeymap
-	case views.ResponsePaneView:
-		keymap = ResponsePaneKeymap
-	case views.SelectMethodDialogView:
-		keymap = SelectMethodDialogKeymap
-	case views.TextInputDialogView:
-		keymap = TextInputDialogKeymap
-	case views.TextAreaDialogView:
-		keymap = TextAreaDialogKeymap
+	scope, ok := viewScopes[m.focus]
+	if m.inputScope != "" {
+		scope, ok = m.inputScope, true
 	}
-	m.content = m.renderKeymap(keymap)
+	if !ok {
+		m.content = ""
+		return
+	}
+	m.content = renderKeymap(scope)
+}
+
+// SetNotice shows the result of an action, such as copying to the clipboard
+func (m *NagivationModel) SetNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeError = isError
+}
+
+func (m *NagivationModel) ClearNotice() {
+	m.notice = ""
 }
 
 func (m NagivationModel) Update(msg tea.Msg) (NagivationModel, tea.Cmd) {
@@ -58,7 +71,22 @@ func (m NagivationModel) Update(msg tea.Msg) (NagivationModel, tea.Cmd) {
 }
 
 func (m NagivationModel) View() string {
+	if m.notice != "" {
+		color := styles.FocusBorderColor
+		if m.noticeError {
+			color = styles.StatusErrorColor
+		}
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(m.truncate(m.notice))
+	}
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#61AFEF")).
-		Render(m.content)
+		Foreground(lipgloss.Color(styles.FooterColor)).
+		Render(m.truncate(m.content))
+}
+
+// truncate keeps the navigation bar on a single line
+func (m NagivationModel) truncate(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return ansi.Truncate(s, m.width, "…")
 }
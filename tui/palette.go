@@ -0,0 +1,130 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/messages"
+	"github.com/gabrielfu/agora/tui/views"
+)
+
+// paneCommand is a command palette entry that runs an action
+// of a pane, exactly as if its key was pressed in that pane.
+type paneCommand struct {
+	title  string
+	view   views.View
+	action keys.Action
+}
+
+var paneCommands = []paneCommand{
+	{"Execute request", views.CollectionPaneView, keys.Execute},
+	{"New request", views.CollectionPaneView, keys.New},
+	{"Rename request", views.CollectionPaneView, keys.Rename},
+	{"Delete request", views.CollectionPaneView, keys.Delete},
+	{"Duplicate request", views.CollectionPaneView, keys.Duplicate},
+	{"Move request to collection", views.CollectionPaneView, keys.MoveTo},
+	{"Copy request to collection", views.CollectionPaneView, keys.CopyTo},
+	{"Move request up", views.CollectionPaneView, keys.MoveUp},
+	{"Move request down", views.CollectionPaneView, keys.MoveDown},
+	{"Search requests in collection", views.CollectionPaneView, keys.Search},
+	{"Copy URL of selected request", views.CollectionPaneView, keys.Copy},
+	{"Copy selected request as curl", views.CollectionPaneView, keys.CopyRequest},
+	{"Search in response body", views.ResponsePaneView, keys.Search},
+	{"Filter JSON response with jq", views.ResponsePaneView, keys.Filter},
+	{"Toggle JSON tree view", views.ResponsePaneView, keys.ToggleTree},
+	{"Toggle raw/pretty response body", views.ResponsePaneView, keys.ToggleRaw},
+	{"Copy response body or header value", views.ResponsePaneView, keys.Copy},
+	{"Copy response header", views.ResponsePaneView, keys.CopyRow},
+	{"Save response body to file", views.ResponsePaneView, keys.SaveBody},
+	{"Edit request in $EDITOR", views.RequestPaneView, keys.OpenEditor},
+	{"Bulk edit params or headers", views.RequestPaneView, keys.BulkEdit},
+	{"Enable/disable param or header", views.RequestPaneView, keys.Toggle},
+	{"Copy param or header value, or body", views.RequestPaneView, keys.Copy},
+	{"Copy param or header", views.RequestPaneView, keys.CopyRow},
+	{"Edit URL", views.UrlPaneView, keys.Edit},
+	{"Select method", views.UrlPaneView, keys.SelectMethod},
+	{"Copy URL", views.UrlPaneView, keys.Copy},
+	{"Copy request as curl", views.UrlPaneView, keys.CopyRequest},
+	{"New collection", views.CollectionListPaneView, keys.New},
+	{"Rename collection", views.CollectionListPaneView, keys.Rename},
+	{"Delete collection", views.CollectionListPaneView, keys.Delete},
+	{"Duplicate collection", views.CollectionListPaneView, keys.Duplicate},
+	{"Show trash", views.CollectionListPaneView, keys.Trash},
+	{"Search collections", views.CollectionListPaneView, keys.Search},
+}
+
+// globalCommand is a command palette entry of a global action
+type globalCommand struct {
+	title  string
+	action keys.Action
+	cmd    tea.Cmd
+}
+
+var globalCommands = []globalCommand{
+	{"Find request in workspace", keys.FindRequest, messages.ShowRequestFinderCmd},
+	{"Switch collection", "", messages.ShowCollectionSwitcherCmd},
+	{"Show key bindings", keys.ShowHelp, messages.ShowHelpCmd},
+	{"Undo", keys.Undo, messages.UndoCmd},
+	{"Focus Collection pane", keys.FocusCollection, messages.SetFocusCmd(views.CollectionPaneView)},
+	{"Focus Collections pane", keys.FocusCollections, messages.SetFocusCmd(views.CollectionListPaneView)},
+	{"Focus URL pane", keys.FocusUrl, messages.SetFocusCmd(views.UrlPaneView)},
+	{"Focus Request pane", keys.FocusRequest, messages.SetFocusCmd(views.RequestPaneView)},
+	{"Focus Response pane", keys.FocusResponse, messages.SetFocusCmd(views.ResponsePaneView)},
+	{"Maximize/restore the focused pane", keys.ZoomPane, layoutCmd(keys.ZoomPane)},
+	{"Shrink the collection panes", keys.ShrinkSidebar, layoutCmd(keys.ShrinkSidebar)},
+	{"Grow the collection panes", keys.GrowSidebar, layoutCmd(keys.GrowSidebar)},
+	{"Shrink the request pane", keys.ShrinkRequest, layoutCmd(keys.ShrinkRequest)},
+	{"Grow the request pane", keys.GrowRequest, layoutCmd(keys.GrowRequest)},
+	{"Switch layout", keys.CycleLayout, layoutCmd(keys.CycleLayout)},
+	{"Reset layout", keys.ResetLayout, layoutCmd(keys.ResetLayout)},
+	{"Quit", keys.Quit, tea.Quit},
+}
+
+var paneNames = map[views.View]string{
+	views.CollectionPaneView:     "Collection",
+	views.CollectionListPaneView: "Collections",
+	views.UrlPaneView:            "URL",
+	views.RequestPaneView:        "Request",
+	views.ResponsePaneView:       "Response",
+}
+
+// keyHint returns the first key bound to an action, formatted for display
+func keyHint(scope keys.Scope, action keys.Action) string {
+	bound := keys.Keys(scope, action)
+	if action == "" || len(bound) == 0 {
+		return ""
+	}
+	return keys.FormatKey(bound[0])
+}
+
+// runActionCmd focuses a pane and runs an action in it
+func runActionCmd(view views.View, action keys.Action) tea.Cmd {
+	return tea.Sequence(
+		messages.SetFocusCmd(view),
+		func() tea.Msg { return keys.ActionMsg{Action: action} },
+	)
+}
+
+// paletteItems lists every action of the application
+func paletteItems() []dialogs.FinderItem {
+	var items []dialogs.FinderItem
+	for _, c := range globalCommands {
+		items = append(items, dialogs.FinderItem{
+			Label: c.title,
+			Hint:  keyHint(keys.GlobalScope, c.action),
+			Cmd:   c.cmd,
+		})
+	}
+	for _, c := range paneCommands {
+		hint := paneNames[c.view]
+		if key := keyHint(viewScopes[c.view], c.action); key != "" {
+			hint += " " + key
+		}
+		items = append(items, dialogs.FinderItem{
+			Label: c.title,
+			Hint:  hint,
+			Cmd:   runActionCmd(c.view, c.action),
+		})
+	}
+	return items
+}
@@ -4,9 +4,12 @@ import (
 	"strconv"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/internal"
 	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/states"
 	"github.com/gabrielfu/agora/tui/styles"
@@ -23,15 +26,25 @@ func updateCollectionCmdFunc(collection string) dialogs.TextInputCmdFunc {
 	}
 }
 
+func duplicateCollectionCmdFunc(collection string) dialogs.TextInputCmdFunc {
+	return func(newName string) tea.Cmd {
+		return messages.DuplicateCollectionCmd(collection, newName)
+	}
+}
+
 type CollectionListPaneModel struct {
 	width       int
 	height      int
 	borderColor string
 
 	dctx           *states.DialogContext
+	collections    []string // all collections of the workspace
 	list           list.Model
+	filterInput    textinput.Model
+	filtering      bool // whether the filter is being typed
 	itemDelegate   *simpleItemDelegate
 	editNameDialog dialogs.TextInputDialog
+	confirmDialog  dialogs.ConfirmDialog
 }
 
 func NewCollectionListPaneModel(dctx *states.DialogContext) CollectionListPaneModel {
@@ -43,10 +56,15 @@ func NewCollectionListPaneModel(dctx *states.DialogContext) CollectionListPaneMo
 	l.SetShowHelp(false)
 	l.SetShowFilter(false)
 	l.SetShowPagination(false)
+	applyListKeys(&l, keys.CollectionListScope)
+
+	filterInput := textinput.New()
+	filterInput.Prompt = "/"
 
 	return CollectionListPaneModel{
 		dctx:         dctx,
 		list:         l,
+		filterInput:  filterInput,
 		itemDelegate: &itemDelegate,
 		editNameDialog: dialogs.NewTextInputDialog(
 			64,
@@ -55,6 +73,11 @@ func NewCollectionListPaneModel(dctx *states.DialogContext) CollectionListPaneMo
 			nil,
 			views.CollectionListPaneView,
 		),
+		confirmDialog: dialogs.NewConfirmDialog(
+			48,
+			[]string{"Delete"},
+			views.CollectionListPaneView,
+		),
 	}
 }
 
@@ -71,7 +94,16 @@ func (m *CollectionListPaneModel) SetWidth(width int) {
 
 func (m *CollectionListPaneModel) SetHeight(height int) {
 	m.height = height
-	m.list.SetHeight(height)
+	m.resizeList()
+}
+
+// resizeList leaves a line for the filter when it is shown
+func (m *CollectionListPaneModel) resizeList() {
+	if m.showFilter() {
+		m.list.SetHeight(m.height - 1)
+	} else {
+		m.list.SetHeight(m.height)
+	}
 }
 
 func (m *CollectionListPaneModel) SetBorderColor(color string) {
@@ -110,30 +142,88 @@ func (m *CollectionListPaneModel) Blur() {
 }
 
 func (m *CollectionListPaneModel) Focus() {
-	m.itemDelegate.SelectedStyle = selectedSimpleItemStyle
+	m.itemDelegate.SelectedStyle = selectedSimpleItemStyle()
 	m.refreshItemDelegate()
 }
 
 func (m *CollectionListPaneModel) Collections() []string {
-	var collections []string
-	for _, item := range m.list.Items() {
-		if simpleItem, ok := item.(simpleItem); ok {
-			collections = append(collections, simpleItem.value)
-		}
-	}
-	return collections
+	return m.collections
 }
 
 func (m *CollectionListPaneModel) SetCollections(collections []string) {
+	m.collections = collections
+	m.applyFilter()
+	m.Update(nil)
+}
+
+// Filtering reports whether the filter is being typed,
+// in which case keys should not trigger global actions.
+func (m CollectionListPaneModel) Filtering() bool {
+	return m.filtering
+}
+
+// filterActive reports whether only matching collections are shown
+func (m CollectionListPaneModel) filterActive() bool {
+	return m.filterInput.Value() != ""
+}
+
+func (m CollectionListPaneModel) showFilter() bool {
+	return m.filtering || m.filterActive()
+}
+
+func (m *CollectionListPaneModel) startFilter() tea.Cmd {
+	m.filtering = true
+	m.resizeList()
+	return m.filterInput.Focus()
+}
+
+func (m *CollectionListPaneModel) stopFilter() {
+	m.filtering = false
+	m.filterInput.Blur()
+	m.resizeList()
+}
+
+func (m *CollectionListPaneModel) clearFilter() {
+	m.filterInput.SetValue("")
+	m.stopFilter()
+	m.applyFilter()
+}
+
+func (m *CollectionListPaneModel) handleFilterKey(msg tea.KeyMsg) tea.Cmd {
+	switch keys.Lookup(keys.FilterScope, msg) {
+	case keys.Submit:
+		m.stopFilter()
+		return nil
+	case keys.Cancel:
+		m.clearFilter()
+		return nil
+	case keys.CursorUp:
+		m.list.CursorUp()
+		return nil
+	case keys.CursorDown:
+		m.list.CursorDown()
+		return nil
+	}
+	query := m.filterInput.Value()
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if m.filterInput.Value() != query {
+		m.applyFilter()
+		m.list.Select(0)
+	}
+	return cmd
+}
+
+// applyFilter shows the collections matching the filter, best match first
+func (m *CollectionListPaneModel) applyFilter() {
 	var items []list.Item
-	for _, collection := range collections {
-		items = append(items, simpleItem{value: collection})
+	for _, match := range internal.FuzzyFind(m.filterInput.Value(), m.collections) {
+		items = append(items, simpleItem{value: m.collections[match.Index]})
 	}
 	m.list.SetItems(items)
 	if m.list.Index() >= len(items) {
 		m.list.Select(len(items) - 1)
 	}
-	m.Update(nil)
 }
 
 func (m *CollectionListPaneModel) handleSelectCollection(collection string) tea.Cmd {
@@ -161,12 +251,45 @@ func (m *CollectionListPaneModel) handleUpdateCollection() {
 	m.dctx.SetDialog(&m.editNameDialog)
 }
 
+func (m *CollectionListPaneModel) handleDuplicateCollection() {
+	item, ok := m.list.SelectedItem().(simpleItem)
+	if !ok {
+		return
+	}
+	m.editNameDialog.SetCmdFunc(duplicateCollectionCmdFunc(item.value))
+	m.editNameDialog.SetValue(item.value + " copy")
+	m.editNameDialog.Focus()
+	m.dctx.SetDialog(&m.editNameDialog)
+}
+
+func (m *CollectionListPaneModel) handleMoveCollection(direction int) tea.Cmd {
+	item, ok := m.list.SelectedItem().(simpleItem)
+	if !ok {
+		return nil
+	}
+	// indices of a filtered list do not match the catalog
+	if m.filterActive() {
+		return nil
+	}
+	index := m.list.Index() + direction
+	if index < 0 || index >= len(m.list.Items()) {
+		return nil
+	}
+	return messages.ReorderCollectionCmd(item.value, index)
+}
+
 func (m *CollectionListPaneModel) handleDeleteCollection() tea.Cmd {
 	item, ok := m.list.SelectedItem().(simpleItem)
 	if !ok {
 		return nil
 	}
-	return messages.DeleteCollectionCmd(item.value)
+	if len(m.collections) <= 1 {
+		return messages.ShowNoticeCmd("Cannot delete the last collection", true)
+	}
+	m.confirmDialog.SetMessage("Move collection " + item.value + " and all its requests to trash?")
+	m.confirmDialog.SetConfirmCmd(messages.DeleteCollectionCmd(item.value))
+	m.dctx.SetDialog(&m.confirmDialog)
+	return nil
 }
 
 func (m CollectionListPaneModel) Update(msg tea.Msg) (CollectionListPaneModel, tea.Cmd) {
@@ -174,19 +297,38 @@ func (m CollectionListPaneModel) Update(msg tea.Msg) (CollectionListPaneModel, t
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			item := m.list.SelectedItem().(simpleItem)
+	if msg, ok := msg.(tea.KeyMsg); ok && m.filtering {
+		return m, m.handleFilterKey(msg)
+	}
+
+	switch keys.Lookup(keys.CollectionListScope, msg) {
+	case keys.Search:
+		return m, m.startFilter()
+	case keys.ClearSearch:
+		if m.filterActive() {
+			m.clearFilter()
+			return m, nil
+		}
+	case keys.Select:
+		if item, ok := m.list.SelectedItem().(simpleItem); ok {
 			cmds = append(cmds, m.handleSelectCollection(item.value))
-		case "n":
-			m.handleNewCollection()
-		case "r":
-			m.handleUpdateCollection()
-		case "d":
-			cmds = append(cmds, m.handleDeleteCollection())
 		}
+	case keys.New:
+		m.handleNewCollection()
+	case keys.Rename:
+		m.handleUpdateCollection()
+	case keys.Delete:
+		cmds = append(cmds, m.handleDeleteCollection())
+	case keys.Trash:
+		cmds = append(cmds, messages.ShowTrashCmd)
+	case keys.Duplicate:
+		m.handleDuplicateCollection()
+	case keys.MoveUp:
+		cmds = append(cmds, m.handleMoveCollection(-1))
+		m.list.CursorUp()
+	case keys.MoveDown:
+		cmds = append(cmds, m.handleMoveCollection(1))
+		m.list.CursorDown()
 	}
 
 	m.list, cmd = m.list.Update(msg)
@@ -196,5 +338,8 @@ func (m CollectionListPaneModel) Update(msg tea.Msg) (CollectionListPaneModel, t
 
 func (m CollectionListPaneModel) View() string {
 	text := m.list.View()
+	if m.showFilter() {
+		text = lipgloss.JoinVertical(lipgloss.Left, text, m.filterInput.View())
+	}
 	return m.generateStyle().Render(text)
 }
@@ -2,58 +2,95 @@ package panes
 
 import (
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gabrielfu/agora/internal"
 	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/states"
 	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
 )
 
+func moveRequestCmdFunc(req internal.Request) dialogs.SelectCmdFunc {
+	return func(collection string) tea.Cmd {
+		return messages.MoveRequestToCollectionCmd(req, collection)
+	}
+}
+
+func copyRequestCmdFunc(req internal.Request) dialogs.SelectCmdFunc {
+	return func(collection string) tea.Cmd {
+		return messages.CopyRequestToCollectionCmd(req, collection)
+	}
+}
+
 type CollectionPaneModel struct {
 	width       int
 	height      int
 	borderColor string
 
 	collection     string
-	requests       []internal.Request
+	collections    []string
+	allRequests    []internal.Request // all requests of the collection
+	requests       []internal.Request // requests shown in the table
 	table          table.Model
+	tableStyles    table.Styles
+	cellStyle      table.StyleFunc
+	offset         int // first request shown in the table
 	cursor         int
 	rctx           *states.RequestContext
 	dctx           *states.DialogContext
 	editNameDialog dialogs.TextInputDialog
+	selectDialog   dialogs.SelectDialog
+	confirmDialog  dialogs.ConfirmDialog
+	dragging       bool // whether a request is being dragged with the mouse
+	filterInput    textinput.Model
+	filtering      bool   // whether the filter is being typed
+	selectID       string // request to select once it is listed
+	// methods of the rows, shared with the style func of the table
+	rowMethods map[int]string
 }
 
 func NewCollectionPaneModel(rctx *states.RequestContext, dctx *states.DialogContext, collection string) CollectionPaneModel {
+	rowMethods := make(map[int]string)
+	// UNSTABLE: StyleFunc feature was removed from 0.19.0
+	// see https://github.com/charmbracelet/bubbles/pull/586
+	// we are using a commit before 0.19.0
+	// 549d0767b3edee6301709463ace03de1aa5ae72c
+	cellStyle := func(row, col int, value string) lipgloss.Style {
+		if col == 0 { // is method column
+			color := styles.GetMethodColor(rowMethods[row])
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+		}
+		return lipgloss.NewStyle()
+	}
 	t := table.New(
 		table.WithColumns(makeCollectionColumns(0)),
 		table.WithRows(make([]table.Row, 0)),
 		table.WithFocused(true),
 		table.WithStyles(tableStyles()),
-		// UNSTABLE: StyleFunc feature was removed from 0.19.0
-		// see https://github.com/charmbracelet/bubbles/pull/586
-		// we are using a commit before 0.19.0
-		// 549d0767b3edee6301709463ace03de1aa5ae72c
-		table.WithStyleFunc(func(row, col int, value string) lipgloss.Style {
-			if col == 0 { // is method column
-				color := styles.GetMethodColor(value)
-				return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
-			}
-			return lipgloss.NewStyle()
-		}),
+		table.WithStyleFunc(cellStyle),
 	)
 
 	// disable "u" and "d"
 	t.KeyMap.HalfPageUp.SetEnabled(false)
 	t.KeyMap.HalfPageDown.SetEnabled(false)
+	applyTableKeys(&t, keys.CollectionScope)
+
+	filterInput := textinput.New()
+	filterInput.Prompt = "/"
 
 	return CollectionPaneModel{
-		table:      t,
-		collection: collection,
-		rctx:       rctx,
-		dctx:       dctx,
+		table:       t,
+		tableStyles: tableStyles(),
+		cellStyle:   cellStyle,
+		filterInput: filterInput,
+		collection:  collection,
+		rctx:        rctx,
+		dctx:        dctx,
+		rowMethods:  rowMethods,
 		editNameDialog: dialogs.NewTextInputDialog(
 			64,
 			[]string{"Name"},
@@ -61,6 +98,18 @@ func NewCollectionPaneModel(rctx *states.RequestContext, dctx *states.DialogCont
 			updateNameCmd,
 			views.CollectionPaneView,
 		),
+		selectDialog: dialogs.NewSelectDialog(
+			32,
+			10,
+			nil,
+			nil,
+			views.CollectionPaneView,
+		),
+		confirmDialog: dialogs.NewConfirmDialog(
+			48,
+			[]string{"Delete"},
+			views.CollectionPaneView,
+		),
 	}
 }
 
@@ -79,7 +128,17 @@ func (m *CollectionPaneModel) SetWidth(width int) {
 
 func (m *CollectionPaneModel) SetHeight(height int) {
 	m.height = height
-	m.table.SetHeight(height)
+	m.resizeTable()
+}
+
+// resizeTable leaves a line for the filter when it is shown
+func (m *CollectionPaneModel) resizeTable() {
+	if m.showFilter() {
+		m.table.SetHeight(m.height - 1)
+	} else {
+		m.table.SetHeight(m.height)
+	}
+	m.offset = scrollTable(m.table, m.offset)
 }
 
 func (m *CollectionPaneModel) SetBorderColor(color string) {
@@ -90,10 +149,184 @@ func (m *CollectionPaneModel) SetCollection(collection string) {
 	m.collection = collection
 }
 
+// SetCollections sets all collections of the workspace,
+// which are the targets for moving and copying requests.
+func (m *CollectionPaneModel) SetCollections(collections []string) {
+	m.collections = collections
+}
+
+// otherCollections returns all collections except the current one.
+func (m CollectionPaneModel) otherCollections() []string {
+	var collections []string
+	for _, c := range m.collections {
+		if c != m.collection {
+			collections = append(collections, c)
+		}
+	}
+	return collections
+}
+
+func (m *CollectionPaneModel) handleDeleteRequest() {
+	req := m.rctx.Request()
+	name := req.Name
+	if name == "" {
+		name = "this request"
+	}
+	m.confirmDialog.SetMessage("Move " + name + " to trash?")
+	m.confirmDialog.SetConfirmCmd(messages.DeleteRequestCmd(req.ID))
+	m.dctx.SetDialog(&m.confirmDialog)
+}
+
+func (m *CollectionPaneModel) handleMoveRequest() {
+	req := *m.rctx.Request()
+	m.selectDialog.SetTitle([]string{"Move", "to"})
+	m.selectDialog.SetOptions(m.otherCollections())
+	m.selectDialog.SetCmdFunc(moveRequestCmdFunc(req))
+	m.dctx.SetDialog(&m.selectDialog)
+}
+
+func (m *CollectionPaneModel) handleCopyRequestToCollection() {
+	req := *m.rctx.Request()
+	m.selectDialog.SetTitle([]string{"Copy", "to"})
+	m.selectDialog.SetOptions(m.otherCollections())
+	m.selectDialog.SetCmdFunc(copyRequestCmdFunc(req))
+	m.dctx.SetDialog(&m.selectDialog)
+}
+
+// moveRequest moves the request at the cursor to the given index.
+// The move is applied locally right away, so that consecutive moves
+// (e.g. while dragging) do not act on stale rows.
+func (m *CollectionPaneModel) moveRequest(index int) tea.Cmd {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.requests) || index < 0 || index >= len(m.requests) || index == cursor {
+		return nil
+	}
+	// indices of a filtered table do not match the catalog
+	if m.filterActive() {
+		return nil
+	}
+	req := m.requests[cursor]
+	requests := append([]internal.Request(nil), m.requests...)
+	requests = append(requests[:cursor], requests[cursor+1:]...)
+	requests = append(requests[:index], append([]internal.Request{req}, requests[index:]...)...)
+	m.SetRequests(requests)
+	m.table.SetCursor(index)
+	m.cursor = index
+	m.rctx.SetRequest(&m.requests[index])
+	return messages.ReorderRequestCmd(req.ID, index)
+}
+
+// handleMouse selects a request on click and reorders it on drag.
+// The coordinates are relative to the pane.
+func (m *CollectionPaneModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	// the first line is the top border
+	row := tableRowAt(m.table, m.offset, msg.Y-1)
+	switch msg.Action {
+	case tea.MouseActionPress:
+		if msg.Button != tea.MouseButtonLeft {
+			return nil
+		}
+		if row >= 0 && msg.X >= 0 && msg.X < m.width+2 {
+			m.table.SetCursor(row)
+			m.dragging = true
+		}
+	case tea.MouseActionMotion:
+		if !m.dragging {
+			return nil
+		}
+		if row < 0 {
+			// dragged outside of the rows
+			if msg.Y-1 < 0 {
+				row = max(0, m.table.Cursor()-1)
+			} else {
+				row = min(len(m.requests)-1, m.table.Cursor()+1)
+			}
+		}
+		return m.moveRequest(row)
+	case tea.MouseActionRelease:
+		m.dragging = false
+	}
+	return nil
+}
+
 func (m *CollectionPaneModel) SetRequests(requests []internal.Request) {
-	m.requests = requests
+	m.allRequests = requests
+	m.applyFilter()
+}
+
+// Filtering reports whether the filter is being typed,
+// in which case keys should not trigger global actions.
+func (m CollectionPaneModel) Filtering() bool {
+	return m.filtering
+}
+
+// filterActive reports whether only matching requests are shown
+func (m CollectionPaneModel) filterActive() bool {
+	return m.filterInput.Value() != ""
+}
+
+func (m CollectionPaneModel) showFilter() bool {
+	return m.filtering || m.filterActive()
+}
+
+func (m *CollectionPaneModel) startFilter() tea.Cmd {
+	m.filtering = true
+	m.resizeTable()
+	return m.filterInput.Focus()
+}
+
+func (m *CollectionPaneModel) stopFilter() {
+	m.filtering = false
+	m.filterInput.Blur()
+	m.resizeTable()
+}
+
+func (m *CollectionPaneModel) clearFilter() {
+	m.filterInput.SetValue("")
+	m.stopFilter()
+	m.applyFilter()
+}
+
+func (m *CollectionPaneModel) handleFilterKey(msg tea.KeyMsg) tea.Cmd {
+	switch keys.Lookup(keys.FilterScope, msg) {
+	case keys.Submit:
+		m.stopFilter()
+		return nil
+	case keys.Cancel:
+		m.clearFilter()
+		return nil
+	case keys.CursorUp:
+		m.table.MoveUp(1)
+		return nil
+	case keys.CursorDown:
+		m.table.MoveDown(1)
+		return nil
+	}
+	query := m.filterInput.Value()
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if m.filterInput.Value() != query {
+		m.applyFilter()
+		m.table.SetCursor(0)
+	}
+	return cmd
+}
+
+// SelectRequest moves the cursor to the request with the given ID,
+// clearing the filter. If the request is not listed yet, it is
+// selected once the requests are set.
+func (m *CollectionPaneModel) SelectRequest(id string) {
+	m.selectID = id
+	m.clearFilter()
+}
+
+// applyFilter shows the requests matching the filter, best match first
+func (m *CollectionPaneModel) applyFilter() {
+	m.requests = internal.SearchRequests(m.allRequests, m.filterInput.Value())
 	var rows []table.Row
-	for _, request := range requests {
+	clear(m.rowMethods)
+	for i, request := range m.requests {
+		m.rowMethods[i] = request.Method
 		// TODO: cell level color doesn't work yet for bubbles table
 		method := styles.RenderMethod(request.Method)
 		var display string
@@ -110,7 +343,30 @@ func (m *CollectionPaneModel) SetRequests(requests []internal.Request) {
 	if m.table.Cursor() >= len(rows) {
 		m.table.SetCursor(len(rows) - 1)
 	}
-	m.Update(nil)
+	for i, req := range m.requests {
+		if m.selectID != "" && req.ID == m.selectID {
+			m.table.SetCursor(i)
+			m.selectID = ""
+		}
+	}
+	m.syncRequestContext()
+}
+
+// syncRequestContext sets the request at the cursor as the current request.
+// The response is kept as long as the same request stays selected.
+func (m *CollectionPaneModel) syncRequestContext() {
+	cursor := m.table.Cursor()
+	m.offset = scrollTable(m.table, m.offset)
+	if cursor >= 0 && cursor < len(m.requests) {
+		req := &m.requests[cursor]
+		if m.rctx.Empty() || m.rctx.Request().ID != req.ID {
+			m.rctx.Clear()
+		}
+		m.rctx.SetRequest(req)
+	} else if m.cursor != cursor {
+		m.rctx.Clear()
+	}
+	m.cursor = cursor
 }
 
 func (m CollectionPaneModel) generateStyle() lipgloss.Style {
@@ -131,57 +387,92 @@ func (m CollectionPaneModel) generateStyle() lipgloss.Style {
 }
 
 func (m *CollectionPaneModel) Blur() {
-	m.table.SetStyles(tableBlurStyles())
+	m.tableStyles = tableBlurStyles()
+	m.table.SetStyles(m.tableStyles)
 }
 
 func (m *CollectionPaneModel) Focus() {
-	m.table.SetStyles(tableStyles())
+	m.tableStyles = tableStyles()
+	m.table.SetStyles(m.tableStyles)
 }
 
 func (m CollectionPaneModel) Update(msg tea.Msg) (CollectionPaneModel, tea.Cmd) {
 	var cmd tea.Cmd
+	var moveCmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		moveCmd = m.handleMouse(msg)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "x":
-			return m, messages.ExecuteRequestCmd
-		case "enter":
-			return m, messages.SetFocusCmd(views.UrlPaneView)
-		case "n":
-			return m, messages.CreateRequestCmd(*internal.NewRequest("GET", ""))
-		case "r":
-			if !m.rctx.Empty() {
-				m.editNameDialog.SetValue(m.rctx.Request().Name)
-				m.editNameDialog.Focus()
-				m.dctx.SetDialog(&m.editNameDialog)
-			}
-		case "d":
-			if !m.rctx.Empty() {
-				return m, messages.DeleteRequestCmd(m.rctx.Request().ID)
-			}
-		case "c":
-			if !m.rctx.Empty() {
-				return m, messages.CopyRequestCmd(*m.rctx.Request())
-			}
+		if m.filtering {
+			cmd = m.handleFilterKey(msg)
+			m.syncRequestContext()
+			return m, cmd
 		}
 	}
 
+	switch keys.Lookup(keys.CollectionScope, msg) {
+	case keys.Search:
+		return m, m.startFilter()
+	case keys.ClearSearch:
+		if m.filterActive() {
+			m.clearFilter()
+			m.syncRequestContext()
+			return m, nil
+		}
+	case keys.Execute:
+		return m, messages.ExecuteRequestCmd
+	case keys.Copy:
+		if !m.rctx.Empty() {
+			return m, messages.CopyToClipboardCmd("URL", m.rctx.Request().ResolvedURL())
+		}
+	case keys.CopyRequest:
+		if !m.rctx.Empty() {
+			return m, messages.CopyToClipboardCmd("request as curl", m.rctx.Request().Curl())
+		}
+	case keys.Select:
+		return m, messages.SetFocusCmd(views.UrlPaneView)
+	case keys.New:
+		return m, messages.CreateRequestCmd(*internal.NewRequest("GET", ""))
+	case keys.Rename:
+		if !m.rctx.Empty() {
+			m.editNameDialog.SetValue(m.rctx.Request().Name)
+			m.editNameDialog.Focus()
+			m.dctx.SetDialog(&m.editNameDialog)
+		}
+	case keys.Delete:
+		if !m.rctx.Empty() {
+			m.handleDeleteRequest()
+		}
+	case keys.Duplicate:
+		if !m.rctx.Empty() {
+			return m, messages.CopyRequestCmd(*m.rctx.Request())
+		}
+	case keys.MoveTo:
+		if !m.rctx.Empty() {
+			m.handleMoveRequest()
+		}
+	case keys.CopyTo:
+		if !m.rctx.Empty() {
+			m.handleCopyRequestToCollection()
+		}
+	case keys.MoveUp:
+		return m, m.moveRequest(m.table.Cursor() - 1)
+	case keys.MoveDown:
+		return m, m.moveRequest(m.table.Cursor() + 1)
+	}
+
 	// process key messages to the table model
 	m.table, cmd = m.table.Update(msg)
 	// retrieve the request object and set the context
-	cursor := m.table.Cursor()
-	if m.cursor != cursor {
-		m.rctx.Clear()
-		m.cursor = cursor
-	}
-	if cursor >= 0 && cursor < len(m.requests) {
-		m.rctx.SetRequest(&m.requests[cursor])
-	}
-	return m, cmd
+	m.syncRequestContext()
+	return m, tea.Batch(cmd, moveCmd)
 }
 
 func (m CollectionPaneModel) View() string {
-	text := renderTableWithoutHeader(&m.table)
+	text := renderTableRows(m.table, m.tableStyles, m.cellStyle, m.offset)
+	if m.showFilter() {
+		text = lipgloss.JoinVertical(lipgloss.Left, text, m.filterInput.View())
+	}
 	return m.generateStyle().Render(text)
 }
@@ -11,7 +11,9 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/styles"
+	"github.com/mattn/go-runewidth"
 )
 
 type simpleItem struct {
@@ -22,13 +24,15 @@ func (i simpleItem) Title() string       { return i.value }
 func (i simpleItem) Description() string { return i.value }
 func (i simpleItem) FilterValue() string { return i.value }
 
-var (
-	simpleItemStyle         = lipgloss.NewStyle().PaddingLeft(2)
-	selectedSimpleItemStyle = lipgloss.NewStyle().
-				PaddingLeft(2).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Background(lipgloss.Color(styles.SelectedBackgroundColor))
-)
+var simpleItemStyle = lipgloss.NewStyle().PaddingLeft(2)
+
+func selectedSimpleItemStyle() lipgloss.Style {
+	return tableSelectedStyle().PaddingLeft(2)
+}
+
+func focusedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(styles.FocusBorderColor))
+}
 
 type simpleItemDelegate struct {
 	Width         int
@@ -57,16 +61,32 @@ func (d simpleItemDelegate) Render(w io.Writer, m list.Model, index int, listIte
 	fmt.Fprint(w, fn(i.value))
 }
 
-var (
-	tableSelectedStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Background(lipgloss.Color(styles.SelectedBackgroundColor))
-	tableBlurSelectedStyle = lipgloss.NewStyle()
-)
+var tableBlurSelectedStyle = lipgloss.NewStyle()
+
+func tableSelectedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(styles.SelectedForegroundColor)).
+		Background(lipgloss.Color(styles.SelectedBackgroundColor))
+}
+
+// applyTableKeys binds the cursor movement of a table to the keys of a scope
+func applyTableKeys(t *table.Model, scope keys.Scope) {
+	t.KeyMap.LineUp.SetKeys(keys.Keys(scope, keys.CursorUp)...)
+	t.KeyMap.LineDown.SetKeys(keys.Keys(scope, keys.CursorDown)...)
+}
+
+// applyListKeys binds the cursor movement of a list to the keys of a scope.
+// Quitting is left to the root model.
+func applyListKeys(l *list.Model, scope keys.Scope) {
+	l.KeyMap.CursorUp.SetKeys(keys.Keys(scope, keys.CursorUp)...)
+	l.KeyMap.CursorDown.SetKeys(keys.Keys(scope, keys.CursorDown)...)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ForceQuit.SetEnabled(false)
+}
 
 func tableStyles() table.Styles {
 	s := table.DefaultStyles()
-	s.Selected = tableSelectedStyle
+	s.Selected = tableSelectedStyle()
 	s.Cell = lipgloss.NewStyle()
 	return s
 }
@@ -84,6 +104,69 @@ func renderTableWithoutHeader(table *table.Model) string {
 	return ts[len(ts)-1]
 }
 
+// scrollTable returns the first row to show from the table, so that its
+// cursor stays visible, scrolling as little as possible from offset.
+func scrollTable(t table.Model, offset int) int {
+	height, cursor := t.Height(), t.Cursor()
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+height {
+		offset = cursor - height + 1
+	}
+	return max(0, min(offset, len(t.Rows())-height))
+}
+
+// renderTableRows renders the rows of the table from offset, without the
+// header, the way the table renders them. The table does not expose which
+// rows it shows, so a pane that maps lines to rows renders them itself.
+func renderTableRows(t table.Model, s table.Styles, styleFunc table.StyleFunc, offset int) string {
+	cols, rows := t.Columns(), t.Rows()
+	end := min(offset+t.Height(), len(rows))
+	rendered := make([]string, 0, max(0, end-offset))
+	for r := max(0, offset); r < end; r++ {
+		cells := make([]string, 0, len(cols))
+		for i, value := range rows[r] {
+			if i >= len(cols) || cols[i].Width <= 0 {
+				continue
+			}
+			cellStyle := s.Cell
+			if styleFunc != nil {
+				cellStyle = styleFunc(r, i, value)
+				if r == t.Cursor() {
+					cellStyle = cellStyle.Inherit(s.Selected)
+				}
+			}
+			style := lipgloss.NewStyle().Width(cols[i].Width).MaxWidth(cols[i].Width).Inline(true)
+			cells = append(cells, cellStyle.Render(style.Render(runewidth.Truncate(value, cols[i].Width, "…"))))
+		}
+		row := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+		if r == t.Cursor() {
+			row = s.Selected.Render(row)
+		}
+		rendered = append(rendered, row)
+	}
+	return lipgloss.NewStyle().
+		Width(t.Width()).
+		Height(t.Height()).
+		MaxHeight(t.Height()).
+		MaxWidth(t.Width()).
+		Render(strings.Join(rendered, "\n"))
+}
+
+// tableRowAt returns the index of the row rendered at the given line
+// of the rows of the table shown from offset, or -1 if there is none.
+func tableRowAt(t table.Model, offset, line int) int {
+	if line < 0 || line >= t.Height() {
+		return -1
+	}
+	row := offset + line
+	if row < 0 || row >= len(t.Rows()) {
+		return -1
+	}
+	return row
+}
+
 func makeKeyValueColumns(width int) []table.Column {
 	keyWidth := int(float64(width) * 0.4)
 	return []table.Column{
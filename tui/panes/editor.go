@@ -0,0 +1,128 @@
+package panes
+
+import (
+	"encoding/json"
+	"mime"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gabrielfu/agora/internal"
+	"github.com/gabrielfu/agora/tui/messages"
+	"github.com/gabrielfu/agora/tui/styles"
+)
+
+// editorCommand returns the command to edit a file with $VISUAL or $EDITOR,
+// which may include arguments, e.g. "code --wait"
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			args = []string{"notepad"}
+		}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// openInEditor suspends the program to edit content in a temporary file,
+// which is named after pattern as in os.CreateTemp to get the extension
+// right. apply is called with the edited content when the editor exits,
+// unless it is unchanged.
+func openInEditor(content, pattern string, apply func(string) tea.Cmd) tea.Cmd {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return messages.ShowNoticeCmd("Cannot open the editor: "+err.Error(), true)
+	}
+	path := file.Name()
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return messages.ShowNoticeCmd("Cannot open the editor: "+err.Error(), true)
+	}
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return messages.ShowNoticeMsg{Text: "Editor failed: " + err.Error(), Error: true}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return messages.ShowNoticeMsg{Text: "Cannot read the edited file: " + err.Error(), Error: true}
+		}
+		if string(data) == content {
+			return nil
+		}
+		return apply(string(data))()
+	})
+}
+
+// bodyExtension returns the file extension to edit the body of a request
+// with, from its Content-Type header. Bodies are JSON by default.
+func bodyExtension(r internal.Request) string {
+	for _, kv := range r.Headers.Enabled() {
+		if !strings.EqualFold(kv.Key, "Content-Type") {
+			continue
+		}
+		mediaType, _, err := mime.ParseMediaType(kv.Value)
+		if err != nil {
+			break
+		}
+		switch {
+		case strings.HasSuffix(mediaType, "json"):
+			return ".json"
+		case strings.HasSuffix(mediaType, "xml"):
+			return ".xml"
+		case strings.HasSuffix(mediaType, "html"):
+			return ".html"
+		case strings.HasSuffix(mediaType, "yaml"):
+			return ".yaml"
+		default:
+			return ".txt"
+		}
+	}
+	return ".json"
+}
+
+// editBodyInEditor edits the body of the request in the external editor.
+// A JSON body is prettified for editing, and is checked when the editor exits.
+func editBodyInEditor(r internal.Request) tea.Cmd {
+	ext := bodyExtension(r)
+	body := string(r.Body)
+	if ext == ".json" && styles.IsValidJson(body) {
+		body = styles.PrettifyJson(body)
+	}
+	return openInEditor(body, "agora-body-*"+ext, func(edited string) tea.Cmd {
+		edited = strings.TrimRight(edited, "\r\n")
+		update := messages.UpdateRequestWithUndoCmd("edit body", func(r *internal.Request) {
+			r.Body = []byte(edited)
+		})
+		if ext != ".json" || edited == "" {
+			return update
+		}
+		var v any
+		if err := json.Unmarshal([]byte(edited), &v); err != nil {
+			// the body is kept so the changes are not lost
+			return tea.Batch(update, messages.ShowNoticeCmd("Body is not valid JSON: "+err.Error(), true))
+		}
+		return update
+	})
+}
+
+// editKVPairsInEditor edits the params or headers of the request in the
+// external editor, one pair per line
+func editKVPairsInEditor(kvs internal.KVPairs, name, separator string, set func(*internal.Request, internal.KVPairs)) tea.Cmd {
+	return openInEditor(internal.FormatKVPairs(kvs, separator), "agora-"+name+"-*.txt", func(edited string) tea.Cmd {
+		return messages.UpdateRequestWithUndoCmd("edit "+name, func(r *internal.Request) {
+			set(r, internal.ParseKVPairs(edited, separator))
+		})
+	})
+}
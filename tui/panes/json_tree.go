@@ -0,0 +1,341 @@
+package panes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gabrielfu/agora/tui/styles"
+)
+
+type jsonKind int
+
+const (
+	jsonValue jsonKind = iota
+	jsonObject
+	jsonArray
+)
+
+// jsonNode is a value of a JSON document, keeping the order of object keys
+type jsonNode struct {
+	kind      jsonKind
+	key       string // key in the parent object
+	index     int    // index in the parent array, or -1
+	value     string // JSON of a scalar value
+	children  []*jsonNode
+	parent    *jsonNode
+	collapsed bool
+}
+
+// parseJsonTree parses a stream of JSON values, such as the results of a filter
+func parseJsonTree(data string) ([]*jsonNode, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	var roots []*jsonNode
+	for {
+		node, err := parseJsonNode(decoder, nil)
+		if err == io.EOF {
+			return roots, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, node)
+	}
+}
+
+func parseJsonNode(decoder *json.Decoder, parent *jsonNode) (*jsonNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &jsonNode{parent: parent, index: -1}
+	switch token {
+	case json.Delim('{'):
+		node.kind = jsonObject
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			child, err := parseJsonNode(decoder, node)
+			if err != nil {
+				return nil, err
+			}
+			child.key = keyToken.(string)
+			node.children = append(node.children, child)
+		}
+	case json.Delim('['):
+		node.kind = jsonArray
+		for decoder.More() {
+			child, err := parseJsonNode(decoder, node)
+			if err != nil {
+				return nil, err
+			}
+			child.index = len(node.children)
+			node.children = append(node.children, child)
+		}
+	default:
+		var b bytes.Buffer
+		encoder := json.NewEncoder(&b)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(token); err != nil {
+			return nil, err
+		}
+		node.value = strings.TrimSuffix(b.String(), "\n")
+		return node, nil
+	}
+	// consume the closing delimiter
+	if _, err := decoder.Token(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return node, nil
+}
+
+func (n *jsonNode) container() bool {
+	return n.kind != jsonValue
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Path returns the path of the node in jq syntax, e.g. .items[3].name
+func (n *jsonNode) Path() string {
+	var parts []string
+	for node := n; node.parent != nil; node = node.parent {
+		switch {
+		case node.parent.kind == jsonArray:
+			parts = append(parts, fmt.Sprintf("[%d]", node.index))
+		case identifierPattern.MatchString(node.key):
+			parts = append(parts, "."+node.key)
+		default:
+			parts = append(parts, "["+strconv.Quote(node.key)+"]")
+		}
+	}
+	if len(parts) == 0 {
+		return "."
+	}
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		if strings.HasPrefix(parts[i], "[") && b.Len() == 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+func (n *jsonNode) setCollapsedAll(collapsed bool) {
+	if !n.container() {
+		return
+	}
+	n.collapsed = collapsed
+	for _, child := range n.children {
+		child.setCollapsedAll(collapsed)
+	}
+}
+
+// jsonTreeLine is a line of the tree, either a node or the closing bracket of a node
+type jsonTreeLine struct {
+	node    *jsonNode
+	depth   int
+	closing bool
+}
+
+// jsonTree is a foldable view of JSON values
+type jsonTree struct {
+	roots  []*jsonNode
+	lines  []jsonTreeLine
+	cursor int
+}
+
+var errNotJson = errors.New("not a JSON document")
+
+func newJsonTree(data string) (jsonTree, error) {
+	roots, err := parseJsonTree(data)
+	if err != nil {
+		return jsonTree{}, err
+	}
+	if len(roots) == 0 {
+		return jsonTree{}, errNotJson
+	}
+	t := jsonTree{roots: roots}
+	t.flatten()
+	return t, nil
+}
+
+func (t *jsonTree) flatten() {
+	t.lines = t.lines[:0]
+	var walk func(n *jsonNode, depth int)
+	walk = func(n *jsonNode, depth int) {
+		t.lines = append(t.lines, jsonTreeLine{node: n, depth: depth})
+		if !n.container() || n.collapsed {
+			return
+		}
+		for _, child := range n.children {
+			walk(child, depth+1)
+		}
+		t.lines = append(t.lines, jsonTreeLine{node: n, depth: depth, closing: true})
+	}
+	for _, root := range t.roots {
+		walk(root, 0)
+	}
+	t.cursor = max(0, min(t.cursor, len(t.lines)-1))
+}
+
+// Node returns the node under the cursor
+func (t jsonTree) Node() *jsonNode {
+	if t.cursor >= len(t.lines) {
+		return nil
+	}
+	return t.lines[t.cursor].node
+}
+
+// focus moves the cursor to the opening line of a node
+func (t *jsonTree) focus(node *jsonNode) {
+	if i := t.indexOf(node); i >= 0 {
+		t.cursor = i
+	}
+}
+
+func (t *jsonTree) Move(delta int) {
+	t.cursor = max(0, min(len(t.lines)-1, t.cursor+delta))
+}
+
+func (t *jsonTree) SetCursor(line int) {
+	t.Move(line - t.cursor)
+}
+
+// Toggle folds or unfolds the node under the cursor
+func (t *jsonTree) Toggle() {
+	node := t.Node()
+	if node == nil || !node.container() {
+		return
+	}
+	node.collapsed = !node.collapsed
+	t.flatten()
+	t.focus(node)
+}
+
+// Collapse folds the node under the cursor, or moves to its parent
+func (t *jsonTree) Collapse() {
+	node := t.Node()
+	if node == nil {
+		return
+	}
+	if node.container() && !node.collapsed {
+		node.collapsed = true
+		t.flatten()
+		t.focus(node)
+	} else if node.parent != nil {
+		t.focus(node.parent)
+	}
+}
+
+// Expand unfolds the node under the cursor
+func (t *jsonTree) Expand() {
+	if node := t.Node(); node != nil && node.container() && node.collapsed {
+		node.collapsed = false
+		t.flatten()
+		t.focus(node)
+	}
+}
+
+func (t *jsonTree) SetCollapsedAll(collapsed bool) {
+	node := t.Node()
+	for _, root := range t.roots {
+		root.setCollapsedAll(collapsed)
+		// keep the top level open
+		if collapsed && root.container() {
+			root.collapsed = false
+		}
+	}
+	t.flatten()
+	for ; node != nil; node = node.parent {
+		if i := t.indexOf(node); i >= 0 {
+			t.cursor = i
+			return
+		}
+	}
+}
+
+func (t jsonTree) indexOf(node *jsonNode) int {
+	for i, line := range t.lines {
+		if line.node == node && !line.closing {
+			return i
+		}
+	}
+	return -1
+}
+
+// summary describes the children of a folded node, e.g. {…} 3 keys
+func (n *jsonNode) summary() string {
+	count := len(n.children)
+	switch n.kind {
+	case jsonObject:
+		if count == 1 {
+			return "{…} 1 key"
+		}
+		return fmt.Sprintf("{…} %d keys", count)
+	default:
+		if count == 1 {
+			return "[…] 1 item"
+		}
+		return fmt.Sprintf("[…] %d items", count)
+	}
+}
+
+func (t jsonTree) renderLine(line jsonTreeLine) string {
+	n := line.node
+	indent := strings.Repeat("  ", line.depth)
+	if line.closing {
+		if n.kind == jsonObject {
+			return indent + "  }"
+		}
+		return indent + "  ]"
+	}
+
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(styles.CurrentTheme().Json.Key))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.HintColor))
+
+	marker := "  "
+	if n.container() {
+		marker = "▾ "
+		if n.collapsed {
+			marker = "▸ "
+		}
+	}
+	var label string
+	if n.parent != nil && n.parent.kind == jsonObject {
+		label = keyStyle.Render(strconv.Quote(n.key)) + ": "
+	}
+	switch {
+	case !n.container():
+		label += styles.ColorizeJson(n.value)
+	case n.collapsed:
+		label += hintStyle.Render(n.summary())
+	case n.kind == jsonObject:
+		label += "{"
+	default:
+		label += "["
+	}
+	return indent + marker + label
+}
+
+// Render renders the visible lines, truncated to width
+func (t jsonTree) Render(width int) string {
+	lines := make([]string, len(t.lines))
+	for i, line := range t.lines {
+		lines[i] = ansi.Truncate(t.renderLine(line), width, "…")
+	}
+	return strings.Join(lines, "\n")
+}
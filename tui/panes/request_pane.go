@@ -10,14 +10,13 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gabrielfu/agora/internal"
 	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/states"
 	"github.com/gabrielfu/agora/tui/styles"
 	"github.com/gabrielfu/agora/tui/views"
 )
 
-var focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.FocusBorderColor))
-
 type requestPaneTab int
 
 const (
@@ -26,8 +25,17 @@ const (
 	requestBodyTab
 )
 
-func updateParamCmdFunc(cursor int, key string) dialogs.TextInputCmdFunc {
-	return func(value string) tea.Cmd {
+// tableRowKind marks the rows of the table that are not rendered as usual
+type tableRowKind int
+
+const (
+	disabledRow tableRowKind = iota + 1
+	// the title of the path params, below the query params
+	sectionRow
+)
+
+func updateParamCmdFunc(cursor int) dialogs.DoubleTextInputCmdFunc {
+	return func(key, value string) tea.Cmd {
 		return messages.UpdateRequestCmd(func(r *internal.Request) {
 			r.UpdateParam(cursor, key, value)
 		})
@@ -40,8 +48,8 @@ var newParamCmdFunc dialogs.DoubleTextInputCmdFunc = func(key, value string) tea
 	})
 }
 
-func updateHeaderCmdFunc(cursor int, key string) dialogs.TextInputCmdFunc {
-	return func(value string) tea.Cmd {
+func updateHeaderCmdFunc(cursor int) dialogs.DoubleTextInputCmdFunc {
+	return func(key, value string) tea.Cmd {
 		return messages.UpdateRequestCmd(func(r *internal.Request) {
 			r.UpdateHeader(cursor, key, value)
 		})
@@ -54,12 +62,32 @@ var updateBodyCmdFunc dialogs.TextAreaCmdFunc = func(body string) tea.Cmd {
 	})
 }
 
+func updatePathParamCmdFunc(name string) dialogs.TextInputCmdFunc {
+	return func(value string) tea.Cmd {
+		return messages.UpdateRequestCmd(func(r *internal.Request) {
+			r.SetPathParam(name, value)
+		})
+	}
+}
+
 var newHeaderCmdFunc dialogs.DoubleTextInputCmdFunc = func(key, value string) tea.Cmd {
 	return messages.UpdateRequestCmd(func(r *internal.Request) {
 		r.WithHeader(key, value)
 	})
 }
 
+var bulkEditParamsCmdFunc dialogs.TextAreaCmdFunc = func(text string) tea.Cmd {
+	return messages.UpdateRequestWithUndoCmd("edit params", func(r *internal.Request) {
+		r.Params = internal.ParseKVPairs(text, "=")
+	})
+}
+
+var bulkEditHeadersCmdFunc dialogs.TextAreaCmdFunc = func(text string) tea.Cmd {
+	return messages.UpdateRequestWithUndoCmd("edit headers", func(r *internal.Request) {
+		r.Headers = internal.ParseKVPairs(text, ": ")
+	})
+}
+
 type RequestPaneModel struct {
 	width       int
 	height      int
@@ -72,11 +100,17 @@ type RequestPaneModel struct {
 	textInputDialog       dialogs.TextInputDialog
 	doubleTextInputDialog dialogs.DoubleTextInputDialog
 	textAreaDialog        dialogs.TextAreaDialog
+	bulkEditDialog        dialogs.TextAreaDialog
 	viewport              viewport.Model
 	table                 table.Model
+	// shared with the style func of the table
+	rowKinds map[int]tableRowKind
+	// suggested when adding or editing a header
+	headerNames []string
 }
 
 func NewRequestPaneModel(rctx *states.RequestContext, dctx *states.DialogContext) RequestPaneModel {
+	rowKinds := make(map[int]tableRowKind)
 	t := table.New(
 		table.WithColumns(makeKeyValueColumns(0)),
 		table.WithRows(make([]table.Row, 0)),
@@ -84,6 +118,16 @@ func NewRequestPaneModel(rctx *states.RequestContext, dctx *states.DialogContext
 		table.WithStyles(tableStyles()),
 		// UNSTABLE: see https://github.com/charmbracelet/bubbles/pull/586
 		table.WithStyleFunc(func(row, col int, value string) lipgloss.Style {
+			switch rowKinds[row] {
+			case disabledRow:
+				return lipgloss.NewStyle().
+					Foreground(lipgloss.Color(styles.HintColor)).
+					Strikethrough(true)
+			case sectionRow:
+				return lipgloss.NewStyle().
+					Foreground(lipgloss.Color(styles.HintColor)).
+					Bold(true)
+			}
 			if col == 0 { // is key column
 				return lipgloss.NewStyle().
 					Foreground(lipgloss.Color(styles.KeyColor)).
@@ -94,13 +138,16 @@ func NewRequestPaneModel(rctx *states.RequestContext, dctx *states.DialogContext
 	)
 	t.KeyMap.HalfPageUp.SetEnabled(false)
 	t.KeyMap.HalfPageDown.SetEnabled(false)
+	// space toggles the row under the cursor
+	t.KeyMap.PageDown.SetKeys("f", "pgdown")
+	applyTableKeys(&t, keys.RequestScope)
 	return RequestPaneModel{
 		rctx: rctx,
 		dctx: dctx,
 		tab:  requestParamsTab,
 		textInputDialog: dialogs.NewTextInputDialog(
 			64,
-			[]string{"Param"},
+			[]string{"Path param"},
 			nil,
 			nil,
 			views.RequestPaneView,
@@ -122,8 +169,17 @@ func NewRequestPaneModel(rctx *states.RequestContext, dctx *states.DialogContext
 			nil,
 			views.RequestPaneView,
 		),
+		bulkEditDialog: dialogs.NewTextAreaDialog(
+			80,
+			15,
+			nil,
+			nil,
+			nil,
+			views.RequestPaneView,
+		),
 		table:    t,
 		viewport: viewport.New(0, 0),
+		rowKinds: rowKinds,
 	}
 }
 
@@ -144,9 +200,14 @@ func (m *RequestPaneModel) SetBorderColor(color string) {
 	m.borderColor = color
 }
 
+// SetHeaderNames sets the header names to suggest
+func (m *RequestPaneModel) SetHeaderNames(names []string) {
+	m.headerNames = names
+}
+
 func (m RequestPaneModel) renderTabBar() string {
 	tabs := []string{"Params", "Headers", "Body"}
-	tabs[m.tab] = focusedStyle.Render(tabs[m.tab])
+	tabs[m.tab] = focusedStyle().Render(tabs[m.tab])
 	separator := strings.Repeat(lipgloss.RoundedBorder().Bottom, m.width)
 	separator = lipgloss.NewStyle().Foreground(lipgloss.Color(m.borderColor)).Render(separator)
 	return strings.Join(tabs, " - ") + "\n" + separator + "\n"
@@ -181,48 +242,102 @@ func (m RequestPaneModel) generateStyle() lipgloss.Style {
 		Height(m.height)
 }
 
+// paramsCursor returns the index of the query param or the path param
+// under the cursor. ok is false on the title of the path params.
+func (m RequestPaneModel) paramsCursor() (index int, path bool, ok bool) {
+	cursor := m.table.Cursor()
+	params := len(m.rctx.Request().Params)
+	switch {
+	case cursor < 0 || cursor >= len(m.table.Rows()):
+		return 0, false, false
+	case cursor < params:
+		return cursor, false, true
+	case cursor == params:
+		return 0, false, false
+	default:
+		return cursor - params - 1, true, true
+	}
+}
+
 func (m *RequestPaneModel) handleUpdateParam() {
-	cursor, key, value, err := getKeyValueFromTableCursor(&m.table)
+	_, key, value, err := getKeyValueFromTableCursor(&m.table)
 	if err != nil {
 		return
 	}
-	m.textInputDialog.SetCmdFunc(updateParamCmdFunc(cursor, key))
-	m.textInputDialog.SetPrompt(focusedStyle.Render(key + "="))
-	m.textInputDialog.SetValue(value)
-	m.textInputDialog.Focus()
-	m.dctx.SetDialog(&m.textInputDialog)
+	cursor, path, ok := m.paramsCursor()
+	if !ok {
+		return
+	}
+	if path {
+		m.textInputDialog.SetCmdFunc(updatePathParamCmdFunc(key))
+		m.textInputDialog.SetPrompt(focusedStyle().Render(key + " = "))
+		m.textInputDialog.SetValue(value)
+		m.textInputDialog.Focus()
+		m.dctx.SetDialog(&m.textInputDialog)
+		return
+	}
+	m.doubleTextInputDialog.SetCmdFunc(updateParamCmdFunc(cursor))
+	m.doubleTextInputDialog.SetSuggestions(nil, nil)
+	m.doubleTextInputDialog.SetUpperValue(key)
+	m.doubleTextInputDialog.SetLowerValue(value)
+	m.doubleTextInputDialog.FocusLower()
+	m.dctx.SetDialog(&m.doubleTextInputDialog)
 }
 
 func (m *RequestPaneModel) handleNewParam() {
 	m.doubleTextInputDialog.SetCmdFunc(newParamCmdFunc)
+	m.doubleTextInputDialog.SetSuggestions(nil, nil)
+	m.doubleTextInputDialog.SetUpperValue("")
+	m.doubleTextInputDialog.SetLowerValue("")
 	m.doubleTextInputDialog.FocusUpper()
 	m.dctx.SetDialog(&m.doubleTextInputDialog)
 }
 
 func (m *RequestPaneModel) handleDeleteParam() tea.Cmd {
-	cursor, _, _, err := getKeyValueFromTableCursor(&m.table)
-	if err != nil {
+	cursor, path, ok := m.paramsCursor()
+	if !ok {
 		return nil
 	}
-	return messages.UpdateRequestCmd(func(r *internal.Request) {
+	if path {
+		// path params are removed from the URL, so only their value is cleared
+		name := m.rctx.Request().PathParamValues()[cursor].Key
+		return messages.UpdateRequestWithUndoCmd("clear path param", func(r *internal.Request) {
+			r.SetPathParam(name, "")
+		})
+	}
+	return messages.UpdateRequestWithUndoCmd("delete param", func(r *internal.Request) {
 		r.RemoveParamI(cursor)
 	})
 }
 
+func (m *RequestPaneModel) handleToggleParam() tea.Cmd {
+	cursor, path, ok := m.paramsCursor()
+	if !ok || path {
+		return nil
+	}
+	return messages.UpdateRequestCmd(func(r *internal.Request) {
+		r.ToggleParam(cursor)
+	})
+}
+
 func (m *RequestPaneModel) handleUpdateHeader() {
 	cursor, key, value, err := getKeyValueFromTableCursor(&m.table)
 	if err != nil {
 		return
 	}
-	m.textInputDialog.SetCmdFunc(updateHeaderCmdFunc(cursor, key))
-	m.textInputDialog.SetPrompt(focusedStyle.Render(key + "="))
-	m.textInputDialog.SetValue(value)
-	m.textInputDialog.Focus()
-	m.dctx.SetDialog(&m.textInputDialog)
+	m.doubleTextInputDialog.SetCmdFunc(updateHeaderCmdFunc(cursor))
+	m.doubleTextInputDialog.SetSuggestions(m.headerNames, internal.HeaderValueSuggestions)
+	m.doubleTextInputDialog.SetUpperValue(key)
+	m.doubleTextInputDialog.SetLowerValue(value)
+	m.doubleTextInputDialog.FocusLower()
+	m.dctx.SetDialog(&m.doubleTextInputDialog)
 }
 
 func (m *RequestPaneModel) handleNewHeader() {
 	m.doubleTextInputDialog.SetCmdFunc(newHeaderCmdFunc)
+	m.doubleTextInputDialog.SetSuggestions(m.headerNames, internal.HeaderValueSuggestions)
+	m.doubleTextInputDialog.SetUpperValue("")
+	m.doubleTextInputDialog.SetLowerValue("")
 	m.doubleTextInputDialog.FocusUpper()
 	m.dctx.SetDialog(&m.doubleTextInputDialog)
 }
@@ -232,11 +347,21 @@ func (m *RequestPaneModel) handleDeleteHeader() tea.Cmd {
 	if err != nil {
 		return nil
 	}
-	return messages.UpdateRequestCmd(func(r *internal.Request) {
+	return messages.UpdateRequestWithUndoCmd("delete header", func(r *internal.Request) {
 		r.RemoveHeaderI(cursor)
 	})
 }
 
+func (m *RequestPaneModel) handleToggleHeader() tea.Cmd {
+	cursor, _, _, err := getKeyValueFromTableCursor(&m.table)
+	if err != nil {
+		return nil
+	}
+	return messages.UpdateRequestCmd(func(r *internal.Request) {
+		r.ToggleHeader(cursor)
+	})
+}
+
 func (m *RequestPaneModel) handleUpdateBody() {
 	if m.rctx.Empty() {
 		return
@@ -250,14 +375,61 @@ func (m *RequestPaneModel) handleUpdateBody() {
 }
 
 func (m *RequestPaneModel) handleDeleteBody() tea.Cmd {
-	return messages.UpdateRequestCmd(func(r *internal.Request) {
+	return messages.UpdateRequestWithUndoCmd("clear body", func(r *internal.Request) {
 		r.Body = []byte{}
 	})
 }
 
+// handleBulkEdit edits all the params or headers as text, one pair per line
+func (m *RequestPaneModel) handleBulkEdit() {
+	req := m.rctx.Request()
+	switch m.tab {
+	case requestParamsTab:
+		m.bulkEditDialog.SetTitle([]string{"Params"}, []string{"key=value", "# to disable"})
+		m.bulkEditDialog.SetCmdFunc(bulkEditParamsCmdFunc)
+		m.bulkEditDialog.SetValue(internal.FormatKVPairs(req.Params, "="))
+	case requestHeadersTab:
+		m.bulkEditDialog.SetTitle([]string{"Headers"}, []string{"Key: Value", "# to disable"})
+		m.bulkEditDialog.SetCmdFunc(bulkEditHeadersCmdFunc)
+		m.bulkEditDialog.SetValue(internal.FormatKVPairs(req.Headers, ": "))
+	case requestBodyTab:
+		m.handleUpdateBody()
+		return
+	}
+	m.bulkEditDialog.Focus()
+	m.dctx.SetDialog(&m.bulkEditDialog)
+}
+
+// handleOpenEditor edits the params, the headers or the body,
+// depending on the current tab, in the external editor
+func (m *RequestPaneModel) handleOpenEditor() tea.Cmd {
+	req := m.rctx.Request()
+	switch m.tab {
+	case requestParamsTab:
+		return editKVPairsInEditor(req.Params, "params", "=", func(r *internal.Request, kvs internal.KVPairs) {
+			r.Params = kvs
+		})
+	case requestHeadersTab:
+		return editKVPairsInEditor(req.Headers, "headers", ": ", func(r *internal.Request, kvs internal.KVPairs) {
+			r.Headers = kvs
+		})
+	case requestBodyTab:
+		return editBodyInEditor(*req)
+	}
+	return nil
+}
+
+func (m *RequestPaneModel) addKVRow(rows *[]table.Row, kv internal.KVPair) {
+	if !kv.Enabled {
+		m.rowKinds[len(*rows)] = disabledRow
+	}
+	*rows = append(*rows, table.Row{kv.Key, kv.Value})
+}
+
 // Refresh refreshes the table items based on the current tab.
 func (m *RequestPaneModel) Refresh() {
 	rows := make([]table.Row, 0)
+	clear(m.rowKinds)
 	if m.rctx.Empty() {
 		m.viewport.SetContent("")
 		m.table.SetRows(rows)
@@ -266,12 +438,19 @@ func (m *RequestPaneModel) Refresh() {
 	switch m.tab {
 	case requestParamsTab:
 		for _, kv := range m.rctx.Request().Params {
-			rows = append(rows, table.Row{kv.Key, kv.Value})
+			m.addKVRow(&rows, kv)
+		}
+		if pathParams := m.rctx.Request().PathParamValues(); len(pathParams) > 0 {
+			m.rowKinds[len(rows)] = sectionRow
+			rows = append(rows, table.Row{"Path", ""})
+			for _, kv := range pathParams {
+				m.addKVRow(&rows, kv)
+			}
 		}
 		m.table.SetRows(rows)
 	case requestHeadersTab:
 		for _, kv := range m.rctx.Request().Headers {
-			rows = append(rows, table.Row{kv.Key, kv.Value})
+			m.addKVRow(&rows, kv)
 		}
 		m.table.SetRows(rows)
 	case requestBodyTab:
@@ -283,55 +462,109 @@ func (m *RequestPaneModel) Refresh() {
 	}
 }
 
+// handleCopy copies the value or the whole row under the cursor,
+// or the body
+func (m *RequestPaneModel) handleCopy(row bool) tea.Cmd {
+	req := m.rctx.Request()
+	var kvs internal.KVPairs
+	separator := ": "
+	cursor := m.table.Cursor()
+	switch m.tab {
+	case requestParamsTab:
+		index, path, ok := m.paramsCursor()
+		if !ok {
+			return nil
+		}
+		kvs, separator, cursor = req.Params, "=", index
+		if path {
+			kvs = req.PathParamValues()
+		}
+	case requestHeadersTab:
+		kvs = req.Headers
+	case requestBodyTab:
+		if row || len(req.Body) == 0 {
+			return nil
+		}
+		return messages.CopyToClipboardCmd("body", string(req.Body))
+	}
+	if cursor < 0 || cursor >= len(kvs) {
+		return nil
+	}
+	kv := kvs[cursor]
+	if row {
+		return messages.CopyToClipboardCmd(kv.Key, kv.Key+separator+kv.Value)
+	}
+	return messages.CopyToClipboardCmd("value of "+kv.Key, kv.Value)
+}
+
 func (m RequestPaneModel) Update(msg tea.Msg) (RequestPaneModel, tea.Cmd) {
 	var (
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		if m.dctx.Empty() {
-			switch msg.String() {
-			case "esc":
-				return m, messages.SetFocusCmd(views.CollectionPaneView)
-			case "[", "shift+tab":
-				m.switchTab(-1)
-			case "]", "tab":
-				m.switchTab(1)
-			}
+	if m.dctx.Empty() {
+		action := keys.Lookup(keys.RequestScope, msg)
+		switch action {
+		case keys.Back:
+			return m, messages.SetFocusCmd(views.CollectionPaneView)
+		case keys.PrevTab:
+			m.switchTab(-1)
+		case keys.NextTab:
+			m.switchTab(1)
+		}
 
-			if !m.rctx.Empty() {
-				switch msg.String() {
-				case "x":
-					return m, messages.ExecuteRequestCmd
-				case "enter":
-					switch m.tab {
-					case requestParamsTab:
-						m.handleUpdateParam()
-					case requestHeadersTab:
-						m.handleUpdateHeader()
-					case requestBodyTab:
-						m.handleUpdateBody()
-					}
-				case "n":
-					switch m.tab {
-					case requestParamsTab:
-						m.handleNewParam()
-					case requestHeadersTab:
-						m.handleNewHeader()
-					case requestBodyTab:
-						m.handleUpdateBody()
-					}
-				case "d":
-					switch m.tab {
-					case requestParamsTab:
-						cmds = append(cmds, m.handleDeleteParam())
-					case requestHeadersTab:
-						cmds = append(cmds, m.handleDeleteHeader())
-					case requestBodyTab:
-						cmds = append(cmds, m.handleDeleteBody())
-					}
+		if !m.rctx.Empty() {
+			handled := true
+			switch action {
+			case keys.Execute:
+				return m, messages.ExecuteRequestCmd
+			case keys.Edit:
+				switch m.tab {
+				case requestParamsTab:
+					m.handleUpdateParam()
+				case requestHeadersTab:
+					m.handleUpdateHeader()
+				case requestBodyTab:
+					m.handleUpdateBody()
+				}
+			case keys.Toggle:
+				switch m.tab {
+				case requestParamsTab:
+					cmds = append(cmds, m.handleToggleParam())
+				case requestHeadersTab:
+					cmds = append(cmds, m.handleToggleHeader())
 				}
+			case keys.BulkEdit:
+				m.handleBulkEdit()
+			case keys.OpenEditor:
+				cmds = append(cmds, m.handleOpenEditor())
+			case keys.New:
+				switch m.tab {
+				case requestParamsTab:
+					m.handleNewParam()
+				case requestHeadersTab:
+					m.handleNewHeader()
+				case requestBodyTab:
+					m.handleUpdateBody()
+				}
+			case keys.Copy, keys.CopyRow:
+				cmds = append(cmds, m.handleCopy(action == keys.CopyRow))
+			case keys.Delete:
+				switch m.tab {
+				case requestParamsTab:
+					cmds = append(cmds, m.handleDeleteParam())
+				case requestHeadersTab:
+					cmds = append(cmds, m.handleDeleteHeader())
+				case requestBodyTab:
+					cmds = append(cmds, m.handleDeleteBody())
+				}
+			default:
+				handled = false
+			}
+			// keys of handled actions, such as "b" for bulk edit,
+			// must not also scroll the table or the viewport
+			if handled {
+				return m, tea.Batch(cmds...)
 			}
 		}
 	}
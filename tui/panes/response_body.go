@@ -0,0 +1,120 @@
+package panes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gabrielfu/agora/internal"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/styles"
+)
+
+// maxHexDumpSize is the number of bytes of a binary body shown in the hex dump
+const maxHexDumpSize = 64 * 1024
+
+// renderContent renders the body of a response by its content type,
+// or the text as it is received in raw mode. Only the preview of a large
+// body is rendered.
+func (m *ResponsePaneModel) renderContent(resp *internal.Response) string {
+	if !resp.Truncated() {
+		return m.renderBodyContent(resp)
+	}
+	notice := fmt.Sprintf("Showing the first %s of %s", internal.FormatSize(int64(len(resp.Content))), internal.FormatSize(resp.Size))
+	if saveKeys := keys.Keys(keys.ResponseScope, keys.SaveBody); len(saveKeys) > 0 {
+		notice += fmt.Sprintf(", press %s to save the whole body", keys.FormatKey(saveKeys[0]))
+	}
+	notice = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.HintColor)).Render(notice)
+	return notice + "\n" + m.renderBodyContent(resp)
+}
+
+func (m *ResponsePaneModel) renderBodyContent(resp *internal.Response) string {
+	kind := resp.Kind()
+	if kind == internal.ContentBinary || (kind == internal.ContentImage && m.raw) {
+		return m.hexDump(resp)
+	}
+	if kind == internal.ContentImage {
+		return imageSummary(resp)
+	}
+
+	text, err := resp.Text()
+	if err != nil {
+		// show the body as UTF-8 with a warning
+		warning := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.StatusErrorColor)).
+			Render(err.Error())
+		text = warning + "\n" + text
+	}
+	if m.filter != "" {
+		if !resp.Truncated() {
+			return m.filterBody(text)
+		}
+		// the JSON of a preview is cut, so jq would fail to parse it
+		warning := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.StatusErrorColor)).
+			Render("Response too large to filter, showing the preview")
+		text = warning + "\n" + text
+	}
+	if m.raw {
+		return text
+	}
+
+	var formatted string
+	switch {
+	// JSON is recognized whatever the content type
+	case (kind == internal.ContentJson || kind == internal.ContentText) && styles.IsValidJson(text):
+		if m.buildTree(text) {
+			return m.tree.Render(m.viewport.Width)
+		}
+		return strings.TrimSuffix(styles.ColorizeJson(styles.PrettifyJson(text)), "\n")
+	case kind == internal.ContentXml:
+		formatted, err = styles.FormatXml(text)
+	case kind == internal.ContentHtml:
+		formatted, err = styles.FormatHtml(text)
+	case kind == internal.ContentYaml:
+		formatted, err = styles.PrettifyYaml(text)
+		formatted = styles.ColorizeYaml(formatted)
+	case kind == internal.ContentCsv:
+		formatted, err = styles.FormatCsv(text, ',')
+	case kind == internal.ContentTsv:
+		formatted, err = styles.FormatCsv(text, '\t')
+	default:
+		return text
+	}
+	if err != nil {
+		// a malformed document is shown as it is
+		return text
+	}
+	return formatted
+}
+
+func (m ResponsePaneModel) hexDump(resp *internal.Response) string {
+	if len(resp.Content) <= maxHexDumpSize {
+		return styles.HexDump(resp.Content, m.viewport.Width)
+	}
+	more := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(styles.HintColor)).
+		Render(fmt.Sprintf("… %s more", internal.FormatSize(resp.Size-maxHexDumpSize)))
+	return styles.HexDump(resp.Content[:maxHexDumpSize], m.viewport.Width) + "\n" + more
+}
+
+// imageSummary describes an image instead of showing its bytes
+func imageSummary(resp *internal.Response) string {
+	mediaType, _ := resp.MediaType()
+	rows := [][2]string{
+		{"Type", mediaType},
+		{"Size", fmt.Sprintf("%s (%d bytes)", internal.FormatSize(resp.Size), resp.Size)},
+	}
+	if config, format, err := resp.ImageConfig(); err == nil {
+		rows = append(rows,
+			[2]string{"Format", format},
+			[2]string{"Dimensions", fmt.Sprintf("%d × %d", config.Width, config.Height)},
+		)
+	}
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.KeyColor)).Bold(true).Width(12)
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = keyStyle.Render(row[0]) + row[1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,13 +2,19 @@ package panes
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/gabrielfu/agora/internal"
+	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/states"
 	"github.com/gabrielfu/agora/tui/styles"
@@ -29,14 +35,32 @@ type ResponsePaneModel struct {
 	borderColor string
 
 	rctx        *states.RequestContext
+	dctx        *states.DialogContext
 	fingerprint string
 
 	tab      responsePaneTab
 	viewport viewport.Model
 	table    table.Model
+	search   textSearch
+
+	// jq filter of the body, saved in the request
+	filterInput textinput.Model
+	filtering   bool   // whether the filter is being typed
+	filter      string // the filter in use
+	requestID   string
+
+	treeMode bool
+	tree     *jsonTree // the body as a tree, if shown as one
+
+	raw bool // whether the body is shown as it is received
+
+	saveDialog dialogs.TextInputDialog
+	// the result of the last save, shown until the response changes
+	notice      string
+	noticeError bool
 }
 
-func NewResponsePaneModel(rctx *states.RequestContext) ResponsePaneModel {
+func NewResponsePaneModel(rctx *states.RequestContext, dctx *states.DialogContext) ResponsePaneModel {
 	t := table.New(
 		table.WithColumns(makeKeyValueColumns(0)),
 		table.WithRows(make([]table.Row, 0)),
@@ -54,11 +78,24 @@ func NewResponsePaneModel(rctx *states.RequestContext) ResponsePaneModel {
 	)
 	t.KeyMap.HalfPageUp.SetEnabled(false)
 	t.KeyMap.HalfPageDown.SetEnabled(false)
+	applyTableKeys(&t, keys.ResponseScope)
+	filterInput := textinput.New()
+	filterInput.Prompt = "jq> "
 	return ResponsePaneModel{
-		rctx:     rctx,
-		tab:      responseHeadersTab,
-		table:    t,
-		viewport: viewport.New(0, 0),
+		rctx:        rctx,
+		dctx:        dctx,
+		tab:         responseHeadersTab,
+		table:       t,
+		viewport:    viewport.New(0, 0),
+		search:      newTextSearch(),
+		filterInput: filterInput,
+		saveDialog: dialogs.NewTextInputDialog(
+			80,
+			[]string{"Save", "body", "to"},
+			nil,
+			messages.SaveResponseBodyCmd,
+			views.ResponsePaneView,
+		),
 	}
 }
 
@@ -67,12 +104,296 @@ func (m *ResponsePaneModel) SetWidth(width int) {
 	m.table.SetWidth(width)
 	m.table.SetColumns(makeKeyValueColumns(width))
 	m.viewport.Width = width - 2
+	m.search.SetWidth(width - 2)
+	m.filterInput.Width = width - 2 - len(m.filterInput.Prompt) - 1
 }
 
 func (m *ResponsePaneModel) SetHeight(height int) {
 	m.height = height
 	m.table.SetHeight(height - 2)
-	m.viewport.Height = height - 3
+	m.resizeViewport()
+}
+
+// resizeViewport leaves a line for the filter and the search when they are shown
+func (m *ResponsePaneModel) resizeViewport() {
+	m.viewport.Height = m.height - 3
+	if m.search.Show() {
+		m.viewport.Height--
+	}
+	if m.showFilter() {
+		m.viewport.Height--
+	}
+}
+
+// Searching reports whether the search is being typed,
+// in which case keys should not trigger global actions.
+func (m ResponsePaneModel) Searching() bool {
+	return m.search.Searching()
+}
+
+// Filtering reports whether the filter is being typed,
+// in which case keys should not trigger global actions.
+func (m ResponsePaneModel) Filtering() bool {
+	return m.filtering
+}
+
+func (m ResponsePaneModel) showFilter() bool {
+	return m.filtering || m.filterInput.Value() != ""
+}
+
+func (m *ResponsePaneModel) startFilter() tea.Cmd {
+	m.tab = responseBodyTab
+	m.filtering = true
+	m.resizeViewport()
+	m.filterInput.CursorEnd()
+	return m.filterInput.Focus()
+}
+
+func (m *ResponsePaneModel) stopFilter() {
+	m.filtering = false
+	m.filterInput.Blur()
+	m.resizeViewport()
+}
+
+func (m *ResponsePaneModel) handleFilterKey(msg tea.KeyMsg) tea.Cmd {
+	switch keys.Lookup(keys.ResponseFilterScope, msg) {
+	case keys.Submit:
+		m.stopFilter()
+		if m.filterInput.Value() == m.filter {
+			return nil
+		}
+		filter := m.filterInput.Value()
+		m.filter = filter
+		m.renderBody()
+		return messages.UpdateRequestCmd(func(r *internal.Request) {
+			r.ResponseFilter = filter
+		})
+	case keys.Cancel:
+		// restore the filter in use
+		m.filterInput.SetValue(m.filter)
+		m.stopFilter()
+		return nil
+	}
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return cmd
+}
+
+// filterBody applies the filter to a JSON body and colorizes the results
+func (m *ResponsePaneModel) filterBody(body string) string {
+	results, err := internal.FilterJson([]byte(body), m.filter)
+	if err != nil {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.StatusErrorColor)).
+			Render("jq: " + err.Error())
+	}
+	if m.buildTree(strings.Join(results, "\n")) {
+		return m.tree.Render(m.viewport.Width)
+	}
+	for i, result := range results {
+		results[i] = strings.TrimSuffix(styles.ColorizeJson(styles.PrettifyJson(result)), "\n")
+	}
+	return strings.Join(results, "\n")
+}
+
+// buildTree shows JSON values as a tree in tree mode.
+// It reports whether the tree is shown.
+func (m *ResponsePaneModel) buildTree(values string) bool {
+	if !m.treeMode || m.raw {
+		return false
+	}
+	tree, err := newJsonTree(values)
+	if err != nil {
+		return false
+	}
+	m.tree = &tree
+	return true
+}
+
+// renderBody renders the response body, filtered if there is a filter
+func (m *ResponsePaneModel) renderBody() {
+	m.tree = nil
+	var text string
+	if err := m.rctx.Error(); err != nil {
+		text = err.Error()
+	} else if resp := m.rctx.Response(); resp != nil {
+		text = m.renderContent(resp)
+	}
+	text = lipgloss.NewStyle().Width(m.width - 2).Render(text)
+	m.search.SetContent(text)
+	m.highlight()
+}
+
+// renderTree renders the tree again after it is folded or unfolded
+func (m *ResponsePaneModel) renderTree() {
+	m.search.SetContent(m.tree.Render(m.viewport.Width))
+	m.highlight()
+}
+
+// TreeView reports whether the body is shown as a tree
+func (m ResponsePaneModel) TreeView() bool {
+	return m.tree != nil && m.tab == responseBodyTab
+}
+
+// highlight renders the body with the search matches and the tree cursor,
+// and scrolls to the current match or the cursor
+func (m *ResponsePaneModel) highlight() {
+	m.resizeViewport()
+	content := m.search.Render()
+	focus := -1
+	if match := m.search.Current(); match != nil {
+		focus = match.line
+	}
+	if m.tree != nil {
+		lines := strings.Split(content, "\n")
+		if cursor := m.tree.cursor; cursor < len(lines) {
+			// pad the cursor line to the right
+			line := lines[cursor] + strings.Repeat(" ", max(0, m.viewport.Width-ansi.StringWidth(lines[cursor])))
+			lines[cursor] = styles.Highlight(line, []styles.Range{{
+				Start: 0,
+				End:   m.viewport.Width,
+				Style: tableSelectedStyle(),
+			}})
+		}
+		content = strings.Join(lines, "\n")
+		focus = m.tree.cursor
+	}
+	m.viewport.SetContent(content)
+	if focus < 0 {
+		return
+	}
+	if focus < m.viewport.YOffset || focus >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(focus - m.viewport.Height/2)
+	}
+}
+
+// handleTreeKey runs the actions of the tree view.
+// It reports whether msg was handled.
+func (m *ResponsePaneModel) handleTreeKey(msg tea.Msg) (tea.Cmd, bool) {
+	switch keys.Lookup(keys.JsonTreeScope, msg) {
+	case keys.ToggleFold:
+		m.tree.Toggle()
+	case keys.Collapse:
+		m.tree.Collapse()
+	case keys.Expand:
+		m.tree.Expand()
+	case keys.CollapseAll:
+		m.tree.SetCollapsedAll(true)
+	case keys.ExpandAll:
+		m.tree.SetCollapsedAll(false)
+	case keys.CursorUp:
+		m.tree.Move(-1)
+	case keys.CursorDown:
+		m.tree.Move(1)
+	case keys.CopyPath:
+		if node := m.tree.Node(); node != nil {
+			return messages.CopyToClipboardCmd("JSON path", node.Path()), true
+		}
+		return nil, true
+	case keys.ToggleTree:
+		m.treeMode = false
+		m.renderBody()
+		return nil, true
+	default:
+		msg, ok := msg.(tea.KeyMsg)
+		if !ok {
+			return nil, false
+		}
+		switch msg.String() {
+		case "pgup":
+			m.tree.Move(-m.viewport.Height)
+		case "pgdown":
+			m.tree.Move(m.viewport.Height)
+		default:
+			return nil, false
+		}
+	}
+	m.renderTree()
+	return nil, true
+}
+
+// showSaveDialog asks for the path to save the body to,
+// suggesting a file name in the working directory
+func (m *ResponsePaneModel) showSaveDialog() {
+	resp := m.rctx.Response()
+	if resp == nil || m.rctx.Sending() {
+		return
+	}
+	path := resp.FileName(m.rctx.Request().URL)
+	if dir, err := os.Getwd(); err == nil {
+		path = filepath.Join(dir, path)
+	}
+	m.saveDialog.SetValue(path)
+	m.saveDialog.Focus()
+	m.dctx.SetDialog(&m.saveDialog)
+}
+
+// copyValue copies the header, or its whole row, under the cursor,
+// or the body as it is shown with the filter
+func (m *ResponsePaneModel) copyValue(row bool) tea.Cmd {
+	resp := m.rctx.Response()
+	if resp == nil || m.rctx.Sending() {
+		return nil
+	}
+	if m.tab == responseHeadersTab {
+		cursor := m.table.Cursor()
+		if cursor < 0 || cursor >= len(m.table.Rows()) {
+			return nil
+		}
+		header := m.table.Rows()[cursor]
+		if row {
+			return messages.CopyToClipboardCmd(header[0], header[0]+": "+header[1])
+		}
+		return messages.CopyToClipboardCmd("value of "+header[0], header[1])
+	}
+	if row {
+		return nil
+	}
+	if resp.Truncated() {
+		m.SetNotice("The body is too large to copy, save it to a file instead", true)
+		return nil
+	}
+	if kind := resp.Kind(); kind == internal.ContentBinary || kind == internal.ContentImage {
+		m.SetNotice("A binary body cannot be copied, save it to a file instead", true)
+		return nil
+	}
+	text, _ := resp.Text()
+	if filter := m.filter; filter != "" {
+		results, err := internal.FilterJson([]byte(text), filter)
+		if err != nil {
+			return nil
+		}
+		return messages.CopyToClipboardCmd("filtered body", strings.Join(results, "\n"))
+	}
+	return messages.CopyToClipboardCmd("body", text)
+}
+
+// SetNotice shows the result of an action on the response, such as saving the body
+func (m *ResponsePaneModel) SetNotice(notice string, isError bool) {
+	m.notice = notice
+	m.noticeError = isError
+}
+
+// renderProgress describes the download of a response, e.g. 1.5 MiB of 3.0 MiB (50%)
+func renderProgress(received, total int64) string {
+	switch {
+	case received == 0:
+		return "Sending…"
+	case total > 0:
+		return fmt.Sprintf("Receiving %s of %s (%d%%)",
+			internal.FormatSize(received), internal.FormatSize(total), received*100/total)
+	default:
+		return fmt.Sprintf("Receiving %s", internal.FormatSize(received))
+	}
+}
+
+// nextMatch moves to the next match of the search, with the tree cursor
+func (m *ResponsePaneModel) nextMatch(direction int) {
+	m.search.Next(direction)
+	if match := m.search.Current(); match != nil && m.tree != nil {
+		m.tree.SetCursor(match.line)
+	}
+	m.highlight()
 }
 
 func (m *ResponsePaneModel) SetBorderColor(color string) {
@@ -81,7 +402,7 @@ func (m *ResponsePaneModel) SetBorderColor(color string) {
 
 func (m ResponsePaneModel) renderTabBar() string {
 	tabs := []string{"Headers", "Body"}
-	tabs[m.tab] = focusedStyle.Render(tabs[m.tab])
+	tabs[m.tab] = focusedStyle().Render(tabs[m.tab])
 	separator := strings.Repeat(lipgloss.RoundedBorder().Bottom, m.width)
 	separator = lipgloss.NewStyle().Foreground(lipgloss.Color(m.borderColor)).Render(separator)
 	return strings.Join(tabs, " - ") + "\n" + separator + "\n"
@@ -95,6 +416,9 @@ func (m ResponsePaneModel) renderStatus() (text, color string) {
 	if m.rctx.Empty() {
 		return "", styles.DefaultBorderColor
 	}
+	if m.rctx.Sending() {
+		return renderProgress(m.rctx.Progress()), styles.HintColor
+	}
 	if err := m.rctx.Error(); err != nil {
 		text = "Error"
 		color = styles.StatusErrorColor
@@ -109,7 +433,7 @@ func (m ResponsePaneModel) renderStatus() (text, color string) {
 }
 
 func (m ResponsePaneModel) renderDuration() (text string) {
-	if m.rctx.Response() == nil {
+	if m.rctx.Response() == nil || m.rctx.Sending() {
 		return ""
 	}
 	text = m.rctx.Duration().String()
@@ -120,31 +444,43 @@ func (m ResponsePaneModel) renderStatusDuration() string {
 	status, statusColor := m.renderStatus()
 	duration := m.renderDuration()
 
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(statusColor))
+	status = style.Render(status)
+	if m.notice != "" {
+		noticeColor := styles.HintColor
+		if m.noticeError {
+			noticeColor = styles.StatusErrorColor
+		}
+		status += "  " + lipgloss.NewStyle().Foreground(lipgloss.Color(noticeColor)).Render(m.notice)
+	}
 	widthForStatus := m.width - runewidth.StringWidth(duration) - 2
 	if widthForStatus < 0 {
 		status = ""
-	} else if widthForStatus < runewidth.StringWidth(status) {
-		status = runewidth.Truncate(status, widthForStatus, "…")
+	} else if widthForStatus < ansi.StringWidth(status) {
+		status = ansi.Truncate(status, widthForStatus, "…")
 	}
-	numSpaces := widthForStatus - runewidth.StringWidth(status)
+	numSpaces := widthForStatus - ansi.StringWidth(status)
 	spaces := "  "
 	if numSpaces > 0 {
 		spaces += strings.Repeat(" ", numSpaces)
 	}
-	status = lipgloss.NewStyle().
-		MaxHeight(1).
-		Foreground(lipgloss.Color(statusColor)).
-		Render(status)
-	duration = lipgloss.NewStyle().
-		MaxHeight(1).
-		Foreground(lipgloss.Color(statusColor)).
-		Render(duration)
-	return status + spaces + duration + "\n"
+	return status + spaces + style.Render(duration) + "\n"
 }
 
 func (m ResponsePaneModel) generateStyle() lipgloss.Style {
 	var footer []string
 	if m.tab == responseBodyTab && m.viewport.TotalLineCount() > 0 {
+		if m.TreeView() {
+			if node := m.tree.Node(); node != nil {
+				footer = append(footer, node.Path())
+			}
+		}
+		if m.raw {
+			footer = append(footer, "raw")
+		}
+		if matches := m.search.Footer(); matches != "" {
+			footer = append(footer, matches)
+		}
 		footer = append(footer, fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100))
 	} else if m.tab == responseHeadersTab {
 		footer = append(footer, tableFooter(&m.table))
@@ -168,6 +504,8 @@ func (m *ResponsePaneModel) Refresh() {
 	rows := make([]table.Row, 0)
 	if m.rctx.Empty() {
 		m.fingerprint = ""
+		m.requestID = ""
+		m.search.SetContent("")
 		m.viewport.SetContent("")
 		m.table.SetRows(rows)
 		return
@@ -175,16 +513,14 @@ func (m *ResponsePaneModel) Refresh() {
 
 	if m.fingerprint != m.rctx.Fingerprint() {
 		m.fingerprint = m.rctx.Fingerprint()
-
-		var text string
-		if err := m.rctx.Error(); err != nil {
-			text = err.Error()
-		} else if m.rctx.Response() != nil {
-			text = m.rctx.Response().String()
+		m.notice = ""
+		if id := m.rctx.Request().ID; id != m.requestID {
+			m.requestID = id
+			m.filter = m.rctx.Request().ResponseFilter
+			m.filterInput.SetValue(m.filter)
+			m.stopFilter()
 		}
-		text = styles.ColorizeJsonIfValid(text)
-		text = lipgloss.NewStyle().Width(m.width - 2).Render(text)
-		m.viewport.SetContent(text)
+		m.renderBody()
 
 		if m.rctx.Response() != nil {
 			for _, kv := range m.rctx.Response().Headers {
@@ -200,17 +536,60 @@ func (m ResponsePaneModel) Update(msg tea.Msg) (ResponsePaneModel, tea.Cmd) {
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			return m, messages.SetFocusCmd(views.CollectionPaneView)
-		case "[", "shift+tab":
-			m.switchTab(-1)
-		case "]", "tab":
-			m.switchTab(1)
+	if msg, ok := msg.(tea.KeyMsg); ok && m.filtering {
+		return m, m.handleFilterKey(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.search.Searching() {
+		cmd = m.search.HandleKey(msg, m.viewport.YOffset)
+		m.highlight()
+		return m, cmd
+	}
+
+	if m.TreeView() {
+		if cmd, ok := m.handleTreeKey(msg); ok {
+			return m, cmd
 		}
 	}
+
+	switch keys.Lookup(keys.ResponseScope, msg) {
+	case keys.ToggleTree:
+		m.tab = responseBodyTab
+		m.treeMode = !m.treeMode
+		m.raw = false
+		m.renderBody()
+		return m, nil
+	case keys.ToggleRaw:
+		m.tab = responseBodyTab
+		m.raw = !m.raw
+		m.renderBody()
+		return m, nil
+	case keys.Search:
+		m.tab = responseBodyTab
+		cmd = m.search.Start()
+		m.highlight()
+		return m, cmd
+	case keys.Filter:
+		return m, m.startFilter()
+	case keys.SaveBody:
+		m.showSaveDialog()
+		return m, nil
+	case keys.Copy:
+		return m, m.copyValue(false)
+	case keys.CopyRow:
+		return m, m.copyValue(true)
+	case keys.NextMatch:
+		m.nextMatch(1)
+		return m, nil
+	case keys.PrevMatch:
+		m.nextMatch(-1)
+		return m, nil
+	case keys.Back:
+		return m, messages.SetFocusCmd(views.CollectionPaneView)
+	case keys.PrevTab:
+		m.switchTab(-1)
+	case keys.NextTab:
+		m.switchTab(1)
+	}
 	m.viewport, cmd = m.viewport.Update(msg)
 	cmds = append(cmds, cmd)
 	m.table, cmd = m.table.Update(msg)
@@ -234,7 +613,13 @@ func (m ResponsePaneModel) View() string {
 	case responseHeadersTab:
 		text += renderTableWithoutHeader(&m.table)
 	case responseBodyTab:
+		if m.showFilter() {
+			text += m.filterInput.View() + "\n"
+		}
 		text += m.viewport.View()
+		if m.search.Show() {
+			text += "\n" + m.search.View()
+		}
 	}
 	return m.generateStyle().Render(text)
 }
@@ -0,0 +1,242 @@
+package panes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gabrielfu/agora/tui/keys"
+	"github.com/gabrielfu/agora/tui/styles"
+)
+
+// textMatch is a match of a search in a line of text, in runes
+type textMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// textSearch finds a query in rendered lines of text, which may be colored.
+// The query is a plain string, case-insensitive unless it contains
+// an upper case letter, or a regular expression in regex mode.
+type textSearch struct {
+	input     textinput.Model
+	searching bool // whether the query is being typed
+	regex     bool
+	err       error
+
+	lines   []string // rendered lines
+	plain   []string // lines without escape sequences
+	matches []textMatch
+	current int
+}
+
+func newTextSearch() textSearch {
+	input := textinput.New()
+	input.Prompt = "/"
+	return textSearch{input: input}
+}
+
+// Searching reports whether the query is being typed
+func (s textSearch) Searching() bool {
+	return s.searching
+}
+
+// Active reports whether there is a query to highlight
+func (s textSearch) Active() bool {
+	return s.input.Value() != ""
+}
+
+// Show reports whether the search input is shown
+func (s textSearch) Show() bool {
+	return s.searching || s.Active()
+}
+
+func (s *textSearch) SetWidth(width int) {
+	s.input.Width = width - len(s.input.Prompt) - 1
+}
+
+// SetContent sets the lines to search and finds the query again
+func (s *textSearch) SetContent(content string) {
+	s.lines = strings.Split(content, "\n")
+	s.plain = make([]string, len(s.lines))
+	for i, line := range s.lines {
+		s.plain[i] = ansi.Strip(line)
+	}
+	s.find()
+}
+
+// Start starts typing a new query
+func (s *textSearch) Start() tea.Cmd {
+	s.searching = true
+	s.input.SetValue("")
+	s.find()
+	return s.input.Focus()
+}
+
+func (s *textSearch) stop() {
+	s.searching = false
+	s.input.Blur()
+}
+
+func (s *textSearch) Clear() {
+	s.input.SetValue("")
+	s.stop()
+	s.find()
+}
+
+func (s *textSearch) toggleRegex() {
+	s.regex = !s.regex
+	s.input.Prompt = "/"
+	if s.regex {
+		s.input.Prompt = "re/"
+	}
+	s.find()
+}
+
+func (s textSearch) compile() (*regexp.Regexp, error) {
+	query := s.input.Value()
+	if s.regex {
+		return regexp.Compile(query)
+	}
+	pattern := regexp.QuoteMeta(query)
+	if !strings.ContainsFunc(query, unicode.IsUpper) {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// find finds the matches of the query, keeping the current match if possible
+func (s *textSearch) find() {
+	prev := s.Current()
+	s.matches = nil
+	s.err = nil
+	if !s.Active() {
+		return
+	}
+	re, err := s.compile()
+	if err != nil {
+		s.err = err
+		return
+	}
+	for i, line := range s.plain {
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			start := utf8.RuneCountInString(line[:loc[0]])
+			end := start + utf8.RuneCountInString(line[loc[0]:loc[1]])
+			s.matches = append(s.matches, textMatch{line: i, start: start, end: end})
+		}
+	}
+	s.current = 0
+	if prev != nil {
+		s.seek(prev.line)
+	}
+}
+
+// seek makes the first match at or after a line current
+func (s *textSearch) seek(line int) {
+	s.current = 0
+	for i, match := range s.matches {
+		if match.line >= line {
+			s.current = i
+			return
+		}
+	}
+}
+
+// Current returns the current match, if any
+func (s textSearch) Current() *textMatch {
+	if s.current >= len(s.matches) {
+		return nil
+	}
+	return &s.matches[s.current]
+}
+
+// Next moves to the next match, wrapping around
+func (s *textSearch) Next(direction int) {
+	if len(s.matches) == 0 {
+		return
+	}
+	s.current = (s.current + direction + len(s.matches)) % len(s.matches)
+}
+
+// HandleKey updates the query being typed. from is the first visible line,
+// where the search starts when the query changes.
+func (s *textSearch) HandleKey(msg tea.KeyMsg, from int) tea.Cmd {
+	switch keys.Lookup(keys.SearchScope, msg) {
+	case keys.Submit:
+		s.stop()
+		return nil
+	case keys.Cancel:
+		s.Clear()
+		return nil
+	case keys.ToggleRegex:
+		s.toggleRegex()
+		return nil
+	case keys.NextMatch:
+		s.Next(1)
+		return nil
+	case keys.PrevMatch:
+		s.Next(-1)
+		return nil
+	}
+	query := s.input.Value()
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	if s.input.Value() != query {
+		s.find()
+		s.seek(from)
+	}
+	return cmd
+}
+
+// Render returns the lines with the matches highlighted
+func (s textSearch) Render() string {
+	if len(s.matches) == 0 {
+		return strings.Join(s.lines, "\n")
+	}
+	matchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(styles.MatchForegroundColor)).
+		Background(lipgloss.Color(styles.MatchBackgroundColor))
+	currentStyle := tableSelectedStyle()
+
+	ranges := make(map[int][]styles.Range)
+	for i, match := range s.matches {
+		style := matchStyle
+		if i == s.current {
+			style = currentStyle
+		}
+		ranges[match.line] = append(ranges[match.line], styles.Range{Start: match.start, End: match.end, Style: style})
+	}
+	lines := make([]string, len(s.lines))
+	for i, line := range s.lines {
+		lines[i] = styles.Highlight(line, ranges[i])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Footer returns the match count, e.g. "3/17"
+func (s textSearch) Footer() string {
+	switch {
+	case !s.Active():
+		return ""
+	case s.err != nil:
+		return "invalid regex"
+	case len(s.matches) == 0:
+		return "no matches"
+	default:
+		return fmt.Sprintf("%d/%d", s.current+1, len(s.matches))
+	}
+}
+
+func (s textSearch) View() string {
+	return s.input.View()
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gabrielfu/agora/internal"
 	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/states"
 	"github.com/gabrielfu/agora/tui/styles"
@@ -13,7 +14,7 @@ import (
 
 func updateUrlCmd(url string) tea.Cmd {
 	return messages.UpdateRequestCmd(func(r *internal.Request) {
-		r.URL = url
+		r.SetURL(url)
 	})
 }
 
@@ -33,6 +34,8 @@ type UrlPaneModel struct {
 	selectMethodDialog dialogs.SelectMethodDialog
 	editUrlDialog      dialogs.TextInputDialog
 	editNameDialog     dialogs.TextInputDialog
+	// the methods other than the standard ones used in the workspace
+	customMethods []string
 }
 
 func NewUrlPaneModel(rctx *states.RequestContext, dctx *states.DialogContext) UrlPaneModel {
@@ -69,6 +72,11 @@ func (m *UrlPaneModel) SetBorderColor(color string) {
 	m.borderColor = color
 }
 
+// SetCustomMethods sets the methods offered after the standard ones
+func (m *UrlPaneModel) SetCustomMethods(methods []string) {
+	m.customMethods = methods
+}
+
 func (m UrlPaneModel) generateStyle() lipgloss.Style {
 	title := []string{"[3]", "URL"}
 	if !m.rctx.Empty() && m.rctx.Request().Name != "" {
@@ -87,28 +95,29 @@ func (m UrlPaneModel) generateStyle() lipgloss.Style {
 }
 
 func (m UrlPaneModel) Update(msg tea.Msg) (UrlPaneModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			return m, messages.SetFocusCmd(views.CollectionPaneView)
-		}
-
-		if !m.rctx.Empty() {
-			switch msg.String() {
-			case "x":
-				return m, messages.ExecuteRequestCmd
-			case "m":
-				m.dctx.SetDialog(&m.selectMethodDialog)
-			case "enter":
-				m.editUrlDialog.SetValue(m.rctx.Request().URL)
-				m.editUrlDialog.Focus()
-				m.dctx.SetDialog(&m.editUrlDialog)
-			case "r":
-				m.editNameDialog.SetValue(m.rctx.Request().Name)
-				m.editNameDialog.Focus()
-				m.dctx.SetDialog(&m.editNameDialog)
-			}
+	action := keys.Lookup(keys.UrlScope, msg)
+	if action == keys.Back {
+		return m, messages.SetFocusCmd(views.CollectionPaneView)
+	}
+	if !m.rctx.Empty() {
+		switch action {
+		case keys.Execute:
+			return m, messages.ExecuteRequestCmd
+		case keys.Copy:
+			return m, messages.CopyToClipboardCmd("URL", m.rctx.Request().ResolvedURL())
+		case keys.CopyRequest:
+			return m, messages.CopyToClipboardCmd("request as curl", m.rctx.Request().Curl())
+		case keys.SelectMethod:
+			m.selectMethodDialog.SetMethods(m.customMethods, m.rctx.Request().Method)
+			m.dctx.SetDialog(&m.selectMethodDialog)
+		case keys.Edit:
+			m.editUrlDialog.SetValue(m.rctx.Request().QueryURL())
+			m.editUrlDialog.Focus()
+			m.dctx.SetDialog(&m.editUrlDialog)
+		case keys.Rename:
+			m.editNameDialog.SetValue(m.rctx.Request().Name)
+			m.editNameDialog.Focus()
+			m.dctx.SetDialog(&m.editNameDialog)
 		}
 	}
 	return m, nil
@@ -119,8 +128,7 @@ func (m UrlPaneModel) View() string {
 	if !m.rctx.Empty() {
 		request := m.rctx.Request()
 		method := styles.RenderMethodWithColor(request.Method)
-		u := request.URL
-		text = method + " " + u
+		text = method + " " + request.QueryURL()
 	}
 	return m.generateStyle().Render(text)
 }
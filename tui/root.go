@@ -2,12 +2,14 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gabrielfu/agora/internal"
 	"github.com/gabrielfu/agora/tui/dialogs"
+	"github.com/gabrielfu/agora/tui/keys"
 	"github.com/gabrielfu/agora/tui/messages"
 	"github.com/gabrielfu/agora/tui/panes"
 	"github.com/gabrielfu/agora/tui/states"
@@ -17,8 +19,10 @@ import (
 
 // RootModel implements tea.RootModel interface
 type RootModel struct {
-	collectionStore *internal.CollectionStore
-	requestStore    *internal.RequestFileStore
+	storage      *internal.CachedStorage
+	requestStore internal.RequestStorage
+	// version of the storage cache last rendered by the panes
+	listingVersion uint64
 
 	collectionListPane panes.CollectionListPaneModel
 	collectionPane     panes.CollectionPaneModel
@@ -30,72 +34,391 @@ type RootModel struct {
 	focus views.View
 	rctx  *states.RequestContext
 	dctx  *states.DialogContext
+	undo  *states.UndoStack
 
-	width               int
-	height              int
-	collectionPaneWidth float32
-	enoughSpace         bool
+	trashDialog        dialogs.SelectDialog
+	requestFinder      dialogs.FinderDialog
+	commandPalette     dialogs.FinderDialog
+	collectionSwitcher dialogs.SelectDialog
+	helpDialog         dialogs.HelpDialog
+
+	width       int
+	height      int
+	enoughSpace bool
+	layout      Layout
+	geometry    geometry
+	// whether the focused pane is maximized
+	zoomed bool
+	// the last focused pane, which is maximized when zoomed
+	paneFocus views.View
+	// the divider dragged with the mouse
+	resizing divider
 }
 
 type Options func(*RootModel)
 
 func WithCollectionPaneWidth(width float32) Options {
 	return func(m *RootModel) {
-		m.collectionPaneWidth = width
+		m.layout.Sidebar = float64(width)
 	}
 }
 
-func NewRootModel(
-	collectionStore *internal.CollectionStore,
-	requestStore *internal.RequestFileStore,
-	opts ...Options,
-) *RootModel {
+func NewRootModel(s internal.Storage, opts ...Options) (*RootModel, error) {
+	storage := internal.NewCachedStorage(s)
+	requestStore, err := storage.Requests(storage.CurrentCollection())
+	if err != nil {
+		return nil, err
+	}
 	rctx := states.NewRequestContext()
 	dctx := states.NewDialogContext()
 	m := &RootModel{
-		collectionStore:    collectionStore,
+		storage:            storage,
 		requestStore:       requestStore,
 		collectionListPane: panes.NewCollectionListPaneModel(dctx),
-		collectionPane:     panes.NewCollectionPaneModel(rctx, dctx, collectionStore.CurrentCollection()),
+		collectionPane:     panes.NewCollectionPaneModel(rctx, dctx, storage.CurrentCollection()),
 		urlPane:            panes.NewUrlPaneModel(rctx, dctx),
 		requestPane:        panes.NewRequestPaneModel(rctx, dctx),
-		responsePane:       panes.NewResponsePaneModel(rctx),
+		responsePane:       panes.NewResponsePaneModel(rctx, dctx),
 		navigation:         NagivationModel{},
 		focus:              views.CollectionPaneView,
 		rctx:               rctx,
 		dctx:               dctx,
-		enoughSpace:        true,
+		undo:               states.NewUndoStack(),
+		trashDialog: dialogs.NewSelectDialog(
+			64,
+			10,
+			[]string{"Trash"},
+			messages.RestoreFromTrashCmd,
+			views.CollectionListPaneView,
+		),
+		requestFinder: dialogs.NewFinderDialog(
+			80,
+			16,
+			[]string{"Find", "request"},
+			views.CollectionPaneView,
+		),
+		commandPalette: dialogs.NewFinderDialog(
+			80,
+			16,
+			[]string{"Commands"},
+			views.CollectionPaneView,
+		),
+		collectionSwitcher: dialogs.NewSelectDialog(
+			48,
+			10,
+			[]string{"Switch", "collection"},
+			messages.SetCollectionCmd,
+			views.CollectionPaneView,
+		),
+		helpDialog:  dialogs.NewHelpDialog(views.CollectionPaneView),
+		enoughSpace: true,
+		layout:      defaultLayout(),
 	}
 	for _, opt := range opts {
 		opt(m)
 	}
-	return m
+	m.layout = loadLayout(storage, m.layout)
+	if methods, err := internal.CustomMethods(storage); err == nil {
+		m.urlPane.SetCustomMethods(methods)
+	}
+	return m, nil
+}
+
+// Close cancels the request being sent and removes the temporary file of a large response
+func (m *RootModel) Close() {
+	m.rctx.Clear()
 }
 
 func (m RootModel) Init() tea.Cmd {
 	return tea.Batch(
 		textinput.Blink,
 		messages.SetFocusCmd(views.CollectionPaneView),
+		syncStorageCmd(),
 	)
 }
 
+// interval to check the storage for changes made outside of agora
+const syncStorageInterval = time.Second
+
+type syncStorageMsg struct{}
+
+func syncStorageCmd() tea.Cmd {
+	return tea.Tick(syncStorageInterval, func(time.Time) tea.Msg {
+		return syncStorageMsg{}
+	})
+}
+
+// refreshListings updates the panes from the storage cache if it has changed.
+func (m *RootModel) refreshListings() error {
+	version := m.storage.Version()
+	if version == m.listingVersion {
+		return nil
+	}
+	collections, err := m.storage.ListCollections()
+	if err != nil {
+		return err
+	}
+	reqs, err := m.requestStore.ListRequests()
+	if err != nil {
+		return err
+	}
+	m.collectionListPane.SetCollections(collections)
+	m.collectionPane.SetCollections(collections)
+	m.collectionPane.SetRequests(reqs)
+	m.requestPane.SetHeaderNames(internal.HeaderNameSuggestions(reqs))
+	m.listingVersion = version
+	return nil
+}
+
 func (m *RootModel) SetCollection(collection string) {
-	if !m.collectionStore.CollectionExists(collection) {
-		err := m.collectionStore.CreateCollection(collection)
+	if !m.storage.CollectionExists(collection) {
+		err := m.storage.CreateCollection(collection)
 		if err != nil {
 			panic(fmt.Sprintf("error creating collection: %v", err))
 		}
 	}
-	m.collectionStore.SetCurrentCollection(collection)
-	dir := m.collectionStore.CurrentCollectionRequestDir()
-	requestStore, err := internal.NewRequestFileStore(dir)
+	m.storage.SetCurrentCollection(collection)
+	requestStore, err := m.storage.Requests(collection)
 	if err != nil {
 		panic(fmt.Sprintf("error initializing collection store: %v", err))
 	}
 	m.requestStore = requestStore
+	m.listingVersion = 0
 	m.collectionPane.SetCollection(collection)
 }
 
+// moveRequestToCollection creates the request in the target collection, then
+// deletes it from the current one. The copy is removed if the deletion fails,
+// so that the request is never in both collections.
+func (m *RootModel) moveRequestToCollection(req internal.Request, collection string) error {
+	target, err := m.storage.Requests(collection)
+	if err != nil {
+		return err
+	}
+	if err := target.CreateRequest(req); err != nil {
+		return err
+	}
+	if err := m.requestStore.DeleteRequest(req.ID); err != nil {
+		target.DeleteRequest(req.ID)
+		return err
+	}
+	return nil
+}
+
+// deleteCollection moves a collection to the trash. The last collection
+// is kept, as the workspace always has a current collection.
+func (m *RootModel) deleteCollection(collection string) {
+	collections, err := m.storage.ListCollections()
+	if err != nil {
+		m.navigation.SetNotice("Cannot delete the collection: "+err.Error(), true)
+		return
+	}
+	if len(collections) <= 1 {
+		m.navigation.SetNotice("Cannot delete the last collection", true)
+		return
+	}
+	trashID, err := internal.TrashCollection(m.storage, collection)
+	if err != nil {
+		m.navigation.SetNotice("Cannot delete the collection: "+err.Error(), true)
+		return
+	}
+	m.pushRestoreFromTrash("delete collection", trashID)
+	if m.storage.CurrentCollection() == collection {
+		first, _ := m.storage.GetFirstCollection()
+		m.SetCollection(first)
+	}
+}
+
+// pushRestoreFromTrash allows undoing a deletion by restoring the trash item.
+func (m *RootModel) pushRestoreFromTrash(description, trashID string) {
+	m.undo.Push(description, func() error {
+		return internal.RestoreFromTrash(m.storage, trashID)
+	})
+}
+
+// showRequestFinder lists the requests of every collection
+// in a finder that switches to the selected one.
+func (m *RootModel) showRequestFinder() {
+	collections, err := m.storage.ListCollections()
+	if err != nil {
+		return
+	}
+	var items []dialogs.FinderItem
+	for _, collection := range collections {
+		store, err := m.storage.Requests(collection)
+		if err != nil {
+			continue
+		}
+		requests, err := store.ListRequests()
+		if err != nil {
+			continue
+		}
+		for _, req := range requests {
+			label := req.Name
+			if label == "" {
+				label = req.URL
+			}
+			items = append(items, dialogs.FinderItem{
+				Label: fmt.Sprintf("%-7s %s", req.Method, label),
+				Hint:  collection,
+				Cmd:   messages.JumpToRequestCmd(collection, req.ID),
+			})
+		}
+	}
+	m.requestFinder.SetItems(items)
+	m.requestFinder.SetWidth(m.width)
+	m.requestFinder.SetHeight(m.height)
+	m.dctx.SetDialog(&m.requestFinder)
+}
+
+// showCommandPalette lists every action, returning to the focused pane afterwards.
+func (m *RootModel) showCommandPalette() {
+	m.commandPalette.SetItems(paletteItems())
+	m.commandPalette.SetExitView(m.focus)
+	m.commandPalette.SetWidth(m.width)
+	m.commandPalette.SetHeight(m.height)
+	m.dctx.SetDialog(&m.commandPalette)
+}
+
+func (m *RootModel) showCollectionSwitcher() {
+	collections, err := m.storage.ListCollections()
+	if err != nil {
+		return
+	}
+	m.collectionSwitcher.SetOptions(collections)
+	m.collectionSwitcher.SetWidth(m.width)
+	m.collectionSwitcher.SetHeight(m.height)
+	m.dctx.SetDialog(&m.collectionSwitcher)
+}
+
+func (m *RootModel) showHelp() {
+	m.helpDialog.SetExitView(m.focus)
+	m.helpDialog.SetWidth(m.width)
+	m.helpDialog.SetHeight(m.height)
+	m.dctx.SetDialog(&m.helpDialog)
+}
+
+// handleGlobalKey runs the global action of a key, unless the key
+// is typed as input or bound in the focused pane.
+// The returned bool reports whether the key was handled.
+func (m *RootModel) handleGlobalKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.capturingInput() || !views.IsPaneView(m.focus) {
+		return nil, false
+	}
+	if keys.Lookup(viewScopes[m.focus], msg) != "" || keys.Lookup(m.inputScope(), msg) != "" {
+		return nil, false
+	}
+	switch keys.Lookup(keys.GlobalScope, msg) {
+	case keys.Quit:
+		return tea.Quit, true
+	case keys.Undo:
+		return messages.UndoCmd, true
+	case keys.FindRequest:
+		return messages.ShowRequestFinderCmd, true
+	case keys.CommandPalette:
+		return messages.ShowCommandPaletteCmd, true
+	case keys.ShowHelp:
+		return messages.ShowHelpCmd, true
+	case keys.ZoomPane:
+		// a maximized pane may fit when the layout does not
+		m.updateLayout(keys.ZoomPane)
+		return nil, true
+	}
+	if !m.enoughSpace {
+		return nil, false
+	}
+	switch keys.Lookup(keys.GlobalScope, msg) {
+	case keys.FocusCollection:
+		m.setFocus(views.CollectionPaneView)
+	case keys.FocusCollections:
+		m.setFocus(views.CollectionListPaneView)
+	case keys.FocusUrl:
+		m.setFocus(views.UrlPaneView)
+	case keys.FocusRequest:
+		m.setFocus(views.RequestPaneView)
+	case keys.FocusResponse:
+		m.setFocus(views.ResponsePaneView)
+	case keys.ShrinkSidebar, keys.GrowSidebar, keys.ShrinkRequest,
+		keys.GrowRequest, keys.CycleLayout, keys.ResetLayout:
+		m.updateLayout(keys.Lookup(keys.GlobalScope, msg))
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// updateFocusedPane sends a key or action to the focused pane
+func (m *RootModel) updateFocusedPane(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch m.focus {
+	case views.CollectionListPaneView:
+		m.collectionListPane, cmd = m.collectionListPane.Update(msg)
+	case views.CollectionPaneView:
+		m.collectionPane, cmd = m.collectionPane.Update(msg)
+	case views.UrlPaneView:
+		m.urlPane, cmd = m.urlPane.Update(msg)
+	case views.RequestPaneView:
+		m.requestPane, cmd = m.requestPane.Update(msg)
+	case views.ResponsePaneView:
+		m.responsePane, cmd = m.responsePane.Update(msg)
+	}
+	return cmd
+}
+
+// capturingInput reports whether keys are typed into a dialog
+// or a pane, so that they must not trigger global actions.
+func (m RootModel) capturingInput() bool {
+	return !m.dctx.Empty() || m.collectionPane.Filtering() ||
+		m.collectionListPane.Filtering() ||
+		m.responsePane.Searching() || m.responsePane.Filtering()
+}
+
+// inputScope returns the scope of the input typed in the focused pane,
+// or of the mode it is in, such as the JSON tree, if any
+func (m RootModel) inputScope() keys.Scope {
+	switch {
+	case m.focus == views.CollectionPaneView && m.collectionPane.Filtering():
+		return keys.FilterScope
+	case m.focus == views.CollectionListPaneView && m.collectionListPane.Filtering():
+		return keys.FilterScope
+	case m.focus == views.ResponsePaneView && m.responsePane.Searching():
+		return keys.SearchScope
+	case m.focus == views.ResponsePaneView && m.responsePane.Filtering():
+		return keys.ResponseFilterScope
+	case m.focus == views.ResponsePaneView && m.responsePane.TreeView():
+		return keys.JsonTreeScope
+	case m.focus == views.SelectMethodDialogView && m.typingMethod():
+		return keys.TextInputDialogScope
+	}
+	return ""
+}
+
+// typingMethod reports whether a method is typed in the method dialog
+func (m RootModel) typingMethod() bool {
+	if m.dctx.Empty() {
+		return false
+	}
+	dialog, ok := m.dctx.Dialog().(*dialogs.SelectMethodDialog)
+	return ok && dialog.Typing()
+}
+
+func (m *RootModel) showTrash() {
+	items, err := m.storage.ListTrash()
+	if err != nil {
+		return
+	}
+	labels := make([]string, len(items))
+	ids := make([]string, len(items))
+	for i, item := range items {
+		labels[i] = item.DeletedAt.Format("2006-01-02 15:04") + "  " + item.String()
+		ids[i] = item.ID
+	}
+	m.trashDialog.SetLabeledOptions(labels, ids)
+	m.trashDialog.SetWidth(m.width)
+	m.trashDialog.SetHeight(m.height)
+	m.dctx.SetDialog(&m.trashDialog)
+}
+
 func (m *RootModel) setFocus(v views.View) {
 	m.focus = v
 	m.collectionPane.SetBorderColor(styles.DefaultBorderColor)
@@ -125,6 +448,12 @@ func (m *RootModel) setFocus(v views.View) {
 		m.responsePane.Focus()
 	}
 	m.navigation.SetFocus(v)
+	if views.IsPaneView(v) && v != m.paneFocus {
+		m.paneFocus = v
+		if m.zoomed {
+			m.resize()
+		}
+	}
 }
 
 func (m *RootModel) updatePanes(msg tea.Msg) tea.Cmd {
@@ -154,6 +483,14 @@ func (m *RootModel) updateDialogFocus() {
 			m.setFocus(views.TextInputDialogView)
 		case *dialogs.TextAreaDialog:
 			m.setFocus(views.TextAreaDialogView)
+		case *dialogs.SelectDialog:
+			m.setFocus(views.SelectDialogView)
+		case *dialogs.ConfirmDialog:
+			m.setFocus(views.ConfirmDialogView)
+		case *dialogs.FinderDialog:
+			m.setFocus(views.FinderDialogView)
+		case *dialogs.HelpDialog:
+			m.setFocus(views.HelpDialogView)
 		}
 	}
 }
@@ -162,144 +499,300 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
+	case syncStorageMsg:
+		m.storage.Sync()
+		cmds = append(cmds, syncStorageCmd())
 	case messages.SetFocusMsg:
 		m.setFocus(msg.View)
 	case messages.ExitDialogMsg:
 		m.dctx.Clear()
 		m.setFocus(msg.Dest)
 	case messages.ExecuteRequestMsg:
-		m.rctx.Exec()
+		cmds = append(cmds, m.execRequest())
+	case requestProgressMsg:
+		m.rctx.SetProgress(msg.sequence, msg.received, msg.total)
+		cmds = append(cmds, waitForUpdate(msg.updates))
+	case messages.SaveResponseBodyMsg:
+		cmds = append(cmds, m.saveResponseBody(msg.Path))
+	case responseBodySavedMsg:
+		if msg.err != nil {
+			m.responsePane.SetNotice(msg.err.Error(), true)
+		} else {
+			m.responsePane.SetNotice("Saved to "+msg.path, false)
+		}
+	case requestDoneMsg:
+		m.rctx.Finish(msg.sequence, msg.resp, msg.err, msg.duration)
 	case messages.UpdateRequestMsg:
-		req := m.rctx.Request().Copy()
+		prev := m.rctx.Request().Copy()
+		req := prev.Copy()
 		msg.Func(&req)
-		m.requestStore.UpdateRequest(req)
+		if err := m.requestStore.UpdateRequest(req); err == nil && msg.UndoDescription != "" {
+			collection := m.storage.CurrentCollection()
+			m.undo.Push(msg.UndoDescription, func() error {
+				store, err := m.storage.Requests(collection)
+				if err != nil {
+					return err
+				}
+				return store.UpdateRequest(prev)
+			})
+		}
 	case messages.CreateRequestMsg:
 		m.requestStore.CreateRequest(msg.Req)
 	case messages.DeleteRequestMsg:
-		m.requestStore.DeleteRequest(msg.ID)
+		trashID, err := internal.TrashRequest(m.storage, m.storage.CurrentCollection(), msg.ID)
+		if err != nil {
+			m.navigation.SetNotice("Cannot delete the request: "+err.Error(), true)
+			break
+		}
+		m.pushRestoreFromTrash("delete request", trashID)
 		m.rctx.Clear()
 	case messages.CopyRequestMsg:
 		newReq := msg.Req.CopyWithNewID()
 		m.requestStore.CreateRequest(newReq)
+	case messages.MoveRequestToCollectionMsg:
+		if err := m.moveRequestToCollection(msg.Req, msg.Collection); err != nil {
+			m.navigation.SetNotice("Cannot move the request: "+err.Error(), true)
+			break
+		}
+		m.rctx.Clear()
+	case messages.CopyRequestToCollectionMsg:
+		target, err := m.storage.Requests(msg.Collection)
+		if err == nil {
+			err = target.CreateRequest(msg.Req.CopyWithNewID())
+		}
+		if err != nil {
+			m.navigation.SetNotice("Cannot copy the request: "+err.Error(), true)
+		}
+	case messages.DuplicateCollectionMsg:
+		if err := m.storage.DuplicateCollection(msg.Src, msg.Dst); err != nil {
+			m.navigation.SetNotice("Cannot duplicate the collection: "+err.Error(), true)
+		}
+	case messages.ReorderRequestMsg:
+		reqs, err := m.requestStore.ListRequests()
+		if err != nil {
+			break
+		}
+		catalog := make([]string, len(reqs))
+		for i, req := range reqs {
+			catalog[i] = req.ID
+		}
+		m.requestStore.WriteCatalog(internal.ReorderCatalog(catalog, msg.ID, msg.Index))
 	case messages.SetCollectionMsg:
 		m.SetCollection(msg.Collection)
 		m.rctx.Clear()
 	case messages.CreateCollectionMsg:
 		m.SetCollection(msg.Collection)
 		m.rctx.Clear()
+	case messages.ReorderCollectionMsg:
+		collections, err := m.storage.ListCollections()
+		if err != nil {
+			break
+		}
+		m.storage.WriteCollectionCatalog(internal.ReorderCatalog(collections, msg.Collection, msg.Index))
 	case messages.UpdateCollectionMsg:
-		m.collectionStore.RenameCollection(msg.OldName, msg.NewName)
-		if m.collectionStore.CurrentCollection() == msg.OldName {
-			m.collectionStore.SetCurrentCollection(msg.NewName)
-			dir := m.collectionStore.CurrentCollectionRequestDir()
-			requestStore, _ := internal.NewRequestFileStore(dir)
-			m.requestStore = requestStore
+		m.storage.RenameCollection(msg.OldName, msg.NewName)
+		if m.storage.CurrentCollection() == msg.OldName {
+			m.storage.SetCurrentCollection(msg.NewName)
+			if requestStore, err := m.storage.Requests(msg.NewName); err == nil {
+				m.requestStore = requestStore
+				m.listingVersion = 0
+			}
 		}
 	case messages.DeleteCollectionMsg:
-		collections, _ := m.collectionStore.ListCollections()
-		if len(collections) > 1 {
-			m.collectionStore.DeleteCollection(msg.Collection)
-			if m.collectionStore.CurrentCollection() == msg.Collection {
-				collection, _ := m.collectionStore.GetFirstCollection()
-				m.SetCollection(collection)
+		m.deleteCollection(msg.Collection)
+	case messages.ShowTrashMsg:
+		m.showTrash()
+	case messages.RestoreFromTrashMsg:
+		if err := internal.RestoreFromTrash(m.storage, msg.ID); err != nil {
+			m.navigation.SetNotice("Cannot restore from trash: "+err.Error(), true)
+		}
+	case messages.ShowRequestFinderMsg:
+		m.showRequestFinder()
+	case messages.ShowCommandPaletteMsg:
+		m.showCommandPalette()
+	case messages.ShowCollectionSwitcherMsg:
+		m.showCollectionSwitcher()
+	case messages.ShowHelpMsg:
+		m.showHelp()
+	case layoutMsg:
+		m.updateLayout(msg.action)
+	case messages.CopyToClipboardMsg:
+		if err := writeClipboard(msg.Text); err != nil {
+			m.navigation.SetNotice("Cannot copy to the clipboard: "+err.Error(), true)
+		} else {
+			m.navigation.SetNotice("Copied "+msg.Description, false)
+		}
+	case messages.ShowNoticeMsg:
+		m.navigation.SetNotice(msg.Text, msg.Error)
+	case messages.AddCustomMethodMsg:
+		if err := internal.AddCustomMethod(m.storage, msg.Method); err != nil {
+			m.navigation.SetNotice("Cannot save the method: "+err.Error(), true)
+		} else if methods, err := internal.CustomMethods(m.storage); err == nil {
+			m.urlPane.SetCustomMethods(methods)
+		}
+	case messages.JumpToRequestMsg:
+		if m.storage.CollectionExists(msg.Collection) {
+			m.SetCollection(msg.Collection)
+			m.collectionPane.SelectRequest(msg.ID)
+			m.setFocus(views.CollectionPaneView)
+		}
+	case messages.UndoMsg:
+		if action, ok := m.undo.Pop(); ok {
+			if err := action.Undo(); err != nil {
+				m.undo.Push(action.Description, action.Undo)
+				m.navigation.SetNotice("Cannot undo "+action.Description+": "+err.Error(), true)
+				break
 			}
+			m.rctx.Clear()
 		}
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			if m.dctx.Empty() {
-				return m, tea.Quit
-			}
+		m.navigation.ClearNotice()
+		if cmd, ok := m.handleGlobalKey(msg); ok {
+			cmds = append(cmds, cmd)
+			break
 		}
 		if !m.enoughSpace {
 			break
 		}
-		// switch focus
-		if views.IsPaneView(m.focus) {
-			switch msg.String() {
-			case "1":
-				m.setFocus(views.CollectionPaneView)
-			case "2":
-				m.setFocus(views.CollectionListPaneView)
-			case "3":
-				m.setFocus(views.UrlPaneView)
-			case "4":
-				m.setFocus(views.RequestPaneView)
-			case "5":
-				m.setFocus(views.ResponsePaneView)
-			}
-		}
 		if !m.dctx.Empty() {
 			dialog, cmd := m.dctx.Dialog().Update(msg)
 			m.dctx.SetDialog(dialog.(dialogs.Dialog))
 			cmds = append(cmds, cmd)
 		}
-		// update focused pane
-		switch m.focus {
-		case views.CollectionListPaneView:
-			m.collectionListPane, cmd = m.collectionListPane.Update(msg)
-		case views.CollectionPaneView:
+		cmds = append(cmds, m.updateFocusedPane(msg))
+	case keys.ActionMsg:
+		cmds = append(cmds, m.updateFocusedPane(msg))
+	case tea.MouseMsg:
+		if !m.enoughSpace || !m.dctx.Empty() {
+			break
+		}
+		if m.handleResizeMouse(msg) {
+			break
+		}
+		if msg.Action == tea.MouseActionPress && !m.zoomed {
+			if v, ok := m.geometry.paneAt(msg.X, msg.Y); ok && v != m.focus {
+				m.setFocus(v)
+			}
+		}
+		// the collection pane is at the top left corner,
+		// so its coordinates are the same as the window's
+		if m.focus == views.CollectionPaneView {
 			m.collectionPane, cmd = m.collectionPane.Update(msg)
-		case views.UrlPaneView:
-			m.urlPane, cmd = m.urlPane.Update(msg)
-		case views.RequestPaneView:
-			m.requestPane, cmd = m.requestPane.Update(msg)
-		case views.ResponsePaneView:
-			m.responsePane, cmd = m.responsePane.Update(msg)
+			cmds = append(cmds, cmd)
 		}
-		cmds = append(cmds, cmd)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width - 2
 		m.height = msg.Height - 3
-
-		m.enoughSpace = false
-		if m.width >= 78 && m.height >= 12 {
-			m.enoughSpace = true
-		}
-
-		collectionPaneWidth := int(float32(m.width) * m.collectionPaneWidth)
-		m.collectionPane.SetWidth(collectionPaneWidth)
-		m.collectionPane.SetHeight(m.height - 8)
-
-		m.collectionListPane.SetWidth(collectionPaneWidth)
-		m.collectionListPane.SetHeight(6)
-
-		urlPaneWidth := m.width - collectionPaneWidth - 2
-		m.urlPane.SetWidth(urlPaneWidth)
-
-		requestPaneHeight := (m.height - 5) / 2
-		m.requestPane.SetWidth(urlPaneWidth)
-		m.requestPane.SetHeight(requestPaneHeight)
-
-		responsePaneHeight := m.height - 5 - requestPaneHeight
-		m.responsePane.SetWidth(urlPaneWidth)
-		m.responsePane.SetHeight(responsePaneHeight)
+		m.resize()
 
 		m.dctx.SetDialogWidth(m.width)
 		m.dctx.SetDialogHeight(m.height)
+		m.navigation.SetWidth(msg.Width)
 
 		cmd = m.updatePanes(msg)
 		cmds = append(cmds, cmd)
 	}
 
 	// Set requests for collection pane
-	collections, err := m.collectionStore.ListCollections()
-	if err != nil {
+	if err := m.refreshListings(); err != nil {
 		return m, tea.Quit
 	}
-	reqs, err := m.requestStore.ListRequests()
-	if err != nil {
-		return m, tea.Quit
-	}
-	m.collectionListPane.SetCollections(collections)
-	m.collectionPane.SetRequests(reqs)
 	m.requestPane.Refresh()
 	m.responsePane.Refresh()
 	m.updateDialogFocus()
+	m.navigation.SetInputScope(m.inputScope())
 
 	return m, tea.Batch(cmds...)
 }
 
+// resize arranges the panes in the window according to the layout
+func (m *RootModel) resize() {
+	// the navigation bar takes the last line
+	width, height := m.width+2, m.height+2
+	g, ok := computeGeometry(width, height, m.layout)
+	if m.zoomed {
+		g, ok = zoomGeometry(g, width, height, m.paneFocus)
+	}
+	m.enoughSpace = ok
+	m.geometry = g
+
+	m.collectionPane.SetWidth(g.collection.width - 2)
+	m.collectionPane.SetHeight(g.collection.height - 2)
+	m.collectionListPane.SetWidth(g.collections.width - 2)
+	m.collectionListPane.SetHeight(g.collections.height - 2)
+	m.urlPane.SetWidth(g.url.width - 2)
+	m.urlPane.SetHeight(g.url.height - 2)
+	m.requestPane.SetWidth(g.request.width - 2)
+	m.requestPane.SetHeight(g.request.height - 2)
+	m.responsePane.SetWidth(g.response.width - 2)
+	m.responsePane.SetHeight(g.response.height - 2)
+}
+
+// updateLayout applies a layout action and saves the layout
+func (m *RootModel) updateLayout(action keys.Action) {
+	if action == keys.ZoomPane {
+		m.zoomed = !m.zoomed
+		m.resize()
+		return
+	}
+	m.layout = m.layout.update(action)
+	m.resize()
+	if action == keys.CycleLayout {
+		m.navigation.SetNotice("Layout: "+layoutModeNames[m.layout.Mode], false)
+	}
+	m.saveLayout()
+}
+
+func (m *RootModel) saveLayout() {
+	if err := saveLayout(m.storage, m.layout); err != nil {
+		m.navigation.SetNotice("Cannot save the layout: "+err.Error(), true)
+	}
+}
+
+// handleResizeMouse drags the borders between the panes to resize them,
+// and reports whether the mouse event was used
+func (m *RootModel) handleResizeMouse(msg tea.MouseMsg) bool {
+	switch msg.Action {
+	case tea.MouseActionPress:
+		if m.zoomed || msg.Button != tea.MouseButtonLeft {
+			return false
+		}
+		m.resizing = m.geometry.dividerAt(msg.X, msg.Y)
+		return m.resizing != noDivider
+	case tea.MouseActionMotion:
+		if m.resizing == noDivider {
+			return false
+		}
+		m.layout = m.geometry.dragDivider(m.layout, m.resizing, msg.X, msg.Y, m.width+2, m.height+2)
+		m.resize()
+		return true
+	case tea.MouseActionRelease:
+		if m.resizing == noDivider {
+			return false
+		}
+		m.resizing = noDivider
+		m.saveLayout()
+		return true
+	}
+	return false
+}
+
+// paneView renders a single pane
+func (m RootModel) paneView(v views.View) string {
+	switch v {
+	case views.CollectionPaneView:
+		return m.collectionPane.View()
+	case views.CollectionListPaneView:
+		return m.collectionListPane.View()
+	case views.UrlPaneView:
+		return m.urlPane.View()
+	case views.RequestPaneView:
+		return m.requestPane.View()
+	default:
+		return m.responsePane.View()
+	}
+}
+
 func (m RootModel) View() string {
 	if !m.enoughSpace {
 		return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Render(
@@ -317,6 +810,20 @@ func (m RootModel) View() string {
 			lipgloss.Center, lipgloss.Center,
 			m.dctx.View(),
 		)
+	} else if m.zoomed {
+		content = m.paneView(m.paneFocus)
+	} else if m.geometry.mode == stackedLayout {
+		content = lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				m.collectionPane.View(),
+				m.collectionListPane.View(),
+			),
+			m.urlPane.View(),
+			m.requestPane.View(),
+			m.responsePane.View(),
+		)
 	} else {
 		content = lipgloss.JoinHorizontal(
 			lipgloss.Top,
@@ -2,8 +2,7 @@
 package states
 
 import (
-	"io"
-	"strings"
+	"context"
 	"time"
 
 	"github.com/gabrielfu/agora/internal"
@@ -15,6 +14,12 @@ type RequestContext struct {
 	err         error
 	fingerprint string // not a real fingerprint, just a string to identify the state
 	duration    time.Duration
+
+	// the sending of the request in progress, if any
+	cancel   context.CancelFunc
+	sequence int
+	received int64
+	total    int64
 }
 
 func NewRequestContext() *RequestContext {
@@ -47,6 +52,9 @@ func (c *RequestContext) Response() *internal.Response {
 }
 
 func (c *RequestContext) SetResponse(resp *internal.Response) {
+	if c.resp != nil && c.resp != resp {
+		c.resp.Discard()
+	}
 	c.resp = resp
 	c.newFingerprint()
 }
@@ -70,6 +78,10 @@ func (c *RequestContext) SetDuration(d time.Duration) {
 }
 
 func (c *RequestContext) Clear() {
+	c.stop()
+	if c.resp != nil {
+		c.resp.Discard()
+	}
 	c.req = nil
 	c.resp = nil
 	c.err = nil
@@ -77,35 +89,57 @@ func (c *RequestContext) Clear() {
 	c.duration = 0
 }
 
-func (c *RequestContext) Exec() {
-	start := time.Now()
-	response, err := c.req.Exec()
-	duration := time.Since(start)
-	c.duration = duration
-	defer c.newFingerprint()
+// Start marks the request as being sent, cancelling a previous sending.
+// It returns the context of the sending, which is cancelled when the
+// request is cleared, and a number identifying the sending.
+func (c *RequestContext) Start() (context.Context, int) {
+	c.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.sequence++
+	c.received, c.total = 0, -1
+	c.SetResponse(nil)
+	c.err = nil
+	return ctx, c.sequence
+}
 
-	c.err = err
-	if err != nil {
-		return
+func (c *RequestContext) stop() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
 	}
+}
 
-	content, err := io.ReadAll(response.Body)
-	if err != nil {
-		c.err = err
-		return
-	}
+// Sending reports whether the request is being sent
+func (c *RequestContext) Sending() bool {
+	return c.cancel != nil
+}
+
+// Progress returns the number of bytes of the response received,
+// and the size of the response, which is -1 if unknown
+func (c *RequestContext) Progress() (received, total int64) {
+	return c.received, c.total
+}
 
-	var headers internal.KVPairs = make([]internal.KVPair, 0)
-	for k, v := range response.Header {
-		headers = append(headers, internal.KVPair{
-			Key:   k,
-			Value: strings.Join(v, ", "),
-		})
+// SetProgress updates the progress of a sending, if it is the current one
+func (c *RequestContext) SetProgress(sequence int, received, total int64) {
+	if c.Sending() && sequence == c.sequence {
+		c.received, c.total = received, total
 	}
+}
 
-	c.resp = internal.NewResponse(
-		response.StatusCode,
-		content,
-		headers,
-	)
+// Finish sets the result of a sending, if it is the current one. Otherwise
+// the response is discarded. It reports whether the result is set.
+func (c *RequestContext) Finish(sequence int, resp *internal.Response, err error, duration time.Duration) bool {
+	if !c.Sending() || sequence != c.sequence {
+		if resp != nil {
+			resp.Discard()
+		}
+		return false
+	}
+	c.stop()
+	c.duration = duration
+	c.err = err
+	c.SetResponse(resp)
+	return true
 }
@@ -0,0 +1,39 @@
+// Manages the in-session undo history of destructive edits
+package states
+
+// maximum number of actions that can be undone
+const maxUndoActions = 50
+
+type UndoAction struct {
+	Description string
+	Undo        func() error
+}
+
+type UndoStack struct {
+	actions []UndoAction
+}
+
+func NewUndoStack() *UndoStack {
+	return &UndoStack{}
+}
+
+func (s *UndoStack) Empty() bool {
+	return len(s.actions) == 0
+}
+
+func (s *UndoStack) Push(description string, undo func() error) {
+	s.actions = append(s.actions, UndoAction{Description: description, Undo: undo})
+	if len(s.actions) > maxUndoActions {
+		s.actions = s.actions[len(s.actions)-maxUndoActions:]
+	}
+}
+
+// Pop removes and returns the most recent action.
+func (s *UndoStack) Pop() (UndoAction, bool) {
+	if s.Empty() {
+		return UndoAction{}, false
+	}
+	action := s.actions[len(s.actions)-1]
+	s.actions = s.actions[:len(s.actions)-1]
+	return action, true
+}
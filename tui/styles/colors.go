@@ -1,26 +1,25 @@
 package styles
 
-const (
-	DefaultBorderColor = "#DCDFE4"
-	FocusBorderColor   = "#98C379"
+// Colors of the current theme, set by SetTheme
+var (
+	DefaultBorderColor string
+	FocusBorderColor   string
 
-	SelectedBackgroundColor = "#61AFEF"
+	SelectedBackgroundColor string
+	SelectedForegroundColor string
 
-	KeyColor = "#FFA23D"
+	KeyColor    string
+	HintColor   string
+	FooterColor string
 
-	StatusCode100Color     = "#C0A8E1"
-	StatusCode200Color     = "#68D696"
-	StatusCode300Color     = "#EED577"
-	StatusCode400Color     = "#EED577"
-	StatusCode500Color     = "#EF968A"
-	StatusCodeUnknownColor = "#EED577"
-	StatusErrorColor       = "#EF968A"
+	MatchBackgroundColor string
+	MatchForegroundColor string
 
-	MethodGetColor    = "#68D696"
-	MethodPostColor   = "#EED577"
-	MethodPutColor    = "#74AEF6"
-	MethodPatchColor  = "#C0A8E1"
-	MethodDeleteColor = "#EF968A"
-	MethodHeadColor   = "#68D696"
-	MethodOptionColor = "#E55AA8"
+	StatusCode100Color     string
+	StatusCode200Color     string
+	StatusCode300Color     string
+	StatusCode400Color     string
+	StatusCode500Color     string
+	StatusCodeUnknownColor string
+	StatusErrorColor       string
 )
@@ -0,0 +1,61 @@
+package styles
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+)
+
+// csvColumnWidth is the maximum width of a column, longer values are truncated
+const csvColumnWidth = 40
+
+// FormatCsv aligns the columns of comma or tab separated values,
+// with the header row in the key color of the JSON theme
+func FormatCsv(s string, comma rune) (string, error) {
+	reader := csv.NewReader(strings.NewReader(s))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", errors.New("empty document")
+	}
+
+	var widths []int
+	for _, record := range records {
+		for i, field := range record {
+			// keep multi-line fields on one line
+			record[i] = strings.ReplaceAll(strings.ReplaceAll(field, "\r\n", "\n"), "\n", "↵")
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = min(csvColumnWidth, max(widths[i], runewidth.StringWidth(record[i])))
+		}
+	}
+
+	lines := make([]string, len(records))
+	for row, record := range records {
+		var b strings.Builder
+		for i, field := range record {
+			if i > 0 {
+				b.WriteString(" │ ")
+			}
+			field = ansi.Truncate(field, widths[i], "…")
+			if i < len(record)-1 {
+				field += strings.Repeat(" ", widths[i]-runewidth.StringWidth(field))
+			}
+			if row == 0 {
+				field = paint(jsonStyle.Key, field)
+			}
+			b.WriteString(field)
+		}
+		lines[row] = b.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
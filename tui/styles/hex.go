@@ -0,0 +1,45 @@
+package styles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HexDump formats data like `hexdump -C`, with 16 bytes per line,
+// or 8 if the lines would not fit in width
+func HexDump(data []byte, width int) string {
+	perLine := 16
+	if width < 78 {
+		perLine = 8
+	}
+	offsetStyle := ansiPair(lipgloss.NewStyle().Foreground(lipgloss.Color(HintColor)))
+	var lines []string
+	for offset := 0; offset < len(data); offset += perLine {
+		chunk := data[offset:min(offset+perLine, len(data))]
+		var b strings.Builder
+		b.WriteString(paint(offsetStyle, fmt.Sprintf("%08x", offset)))
+		b.WriteString(" ")
+		for i := 0; i < perLine; i++ {
+			if i%8 == 0 {
+				b.WriteString(" ")
+			}
+			if i < len(chunk) {
+				fmt.Fprintf(&b, "%02x ", chunk[i])
+			} else {
+				b.WriteString("   ")
+			}
+		}
+		b.WriteString(" |")
+		for _, c := range chunk {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			b.WriteByte(c)
+		}
+		b.WriteString("|")
+		lines = append(lines, b.String())
+	}
+	return strings.Join(lines, "\n")
+}
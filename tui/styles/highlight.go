@@ -0,0 +1,88 @@
+package styles
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Range is a half-open range of runes in the visible text of a line
+type Range struct {
+	Start int
+	End   int
+	Style lipgloss.Style
+}
+
+// sequenceEnd returns the end of the escape sequence starting at i
+func sequenceEnd(s string, i int) int {
+	if i+1 >= len(s) || s[i+1] != '[' {
+		return min(i+2, len(s))
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7E {
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// Highlight renders ranges of a line that may already be colored,
+// such as colorized JSON. The colors of the line are restored
+// after each range. Ranges must be sorted and must not overlap.
+func Highlight(line string, ranges []Range) string {
+	if len(ranges) == 0 {
+		return line
+	}
+	var (
+		b        strings.Builder
+		active   []string // SGR sequences of the line in effect
+		on       string   // sequence starting the current range
+		inRange  bool
+		pos, cur int // index of the next visible rune and of the next range
+	)
+	restore := func() {
+		if on == "" {
+			return
+		}
+		b.WriteString("\x1b[0m")
+		b.WriteString(strings.Join(active, ""))
+	}
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			end := sequenceEnd(line, i)
+			seq := line[i:end]
+			b.WriteString(seq)
+			if strings.HasSuffix(seq, "m") {
+				if seq == "\x1b[0m" || seq == "\x1b[m" {
+					active = active[:0]
+				} else {
+					active = append(active, seq)
+				}
+			}
+			if inRange {
+				b.WriteString(on)
+			}
+			i = end
+			continue
+		}
+		if inRange && pos == ranges[cur].End {
+			restore()
+			inRange = false
+			cur++
+		}
+		if !inRange && cur < len(ranges) && pos == ranges[cur].Start {
+			on = ansiPair(ranges[cur].Style)[0]
+			b.WriteString(on)
+			inRange = true
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		pos++
+	}
+	if inRange {
+		restore()
+	}
+	return b.String()
+}
@@ -2,10 +2,39 @@ package styles
 
 import (
 	"encoding/json"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/tidwall/pretty"
 )
 
+// jsonStyle is the style of colorized JSON, built from the current theme
+var jsonStyle *pretty.Style
+
+// ansiPair returns the escape sequences that start and end text rendered with style
+func ansiPair(style lipgloss.Style) [2]string {
+	const marker = "\x00"
+	prefix, suffix, _ := strings.Cut(style.Render(marker), marker)
+	return [2]string{prefix, suffix}
+}
+
+func setJsonStyle(t JsonTheme) {
+	color := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+	jsonStyle = &pretty.Style{
+		Key:      ansiPair(color(t.Key).Bold(true)),
+		String:   ansiPair(color(t.String)),
+		Number:   ansiPair(color(t.Number)),
+		True:     ansiPair(color(t.Bool)),
+		False:    ansiPair(color(t.Bool)),
+		Null:     ansiPair(color(t.Null)),
+		Escape:   ansiPair(color(t.Escape)),
+		Brackets: ansiPair(lipgloss.NewStyle().Bold(true)),
+		Append:   pretty.TerminalStyle.Append,
+	}
+}
+
 func IsValidJson(s string) bool {
 	var m any
 	return json.Unmarshal([]byte(s), &m) == nil
@@ -24,7 +53,7 @@ func PrettifyJsonIfValid(s string) string {
 }
 
 func ColorizeJson(s string) string {
-	return string(pretty.Color([]byte(s), nil))
+	return string(pretty.Color([]byte(s), jsonStyle))
 }
 
 func ColorizeJsonIfValid(s string) string {
@@ -1,27 +1,20 @@
 package styles
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 )
 
-var methodColors = map[string]string{
-	"GET":     "#68D696",
-	"POST":    "#EED577",
-	"PUT":     "#74AEF6",
-	"PATCH":   "#C0A8E1",
-	"DELETE":  "#EF968A",
-	"HEAD":    "#68D696",
-	"OPTIONS": "#E55AA8",
-	// short form
-	"GET  ": "#68D696",
-	"POST ": "#EED577",
-	"PUT  ": "#74AEF6",
-	"DEL  ": "#EF968A",
-	"HEAD ": "#68D696",
-	"OPT  ": "#E55AA8",
-}
+// methodColors maps methods to their colors. It is built from the current theme.
+var methodColors = map[string]string{}
+
+// otherMethodColor is the color of the methods without a color in the current theme
+var otherMethodColor string
 
+// methodShort are the short forms of the methods shown in the collection,
+// which are 5 cells wide
 var methodShort = map[string]string{
 	"GET":     "GET  ",
 	"POST":    "POST ",
@@ -30,12 +23,34 @@ var methodShort = map[string]string{
 	"DELETE":  "DEL  ",
 	"HEAD":    "HEAD ",
 	"OPTIONS": "OPT  ",
+	"CONNECT": "CONN ",
+	"TRACE":   "TRACE",
+	// WebDAV, caches and the QUERY draft
+	"PROPFIND":  "PFIND",
+	"PROPPATCH": "PPTCH",
+	"MKCOL":     "MKCOL",
+	"COPY":      "COPY ",
+	"MOVE":      "MOVE ",
+	"LOCK":      "LOCK ",
+	"UNLOCK":    "UNLCK",
+	"REPORT":    "REPRT",
+	"SEARCH":    "SRCH ",
+	"PURGE":     "PURGE",
+	"QUERY":     "QUERY",
+}
+
+func setMethodColors(colors map[string]string, other string) {
+	methodColors = colors
+	otherMethodColor = other
 }
 
 func GetMethodColor(method string) string {
 	if color, ok := methodColors[method]; ok {
 		return color
 	}
+	if otherMethodColor != "" {
+		return otherMethodColor
+	}
 	return methodColors["GET"]
 }
 
@@ -43,7 +58,7 @@ func getMethodShort(method string) string {
 	if short, ok := methodShort[method]; ok {
 		return short
 	}
-	return ansi.Truncate(method, 5, " ")
+	return fmt.Sprintf("%-5s", ansi.Truncate(method, 5, "…"))
 }
 
 func RenderMethod(method string) string {
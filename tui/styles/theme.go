@@ -0,0 +1,321 @@
+package styles
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme is the set of colors used by the application.
+// Colors are anything lipgloss.Color accepts, e.g. "#61AFEF" or "12".
+type Theme struct {
+	Name string `yaml:"-"`
+	// Base is the built-in theme a user theme file starts from
+	Base string `yaml:"base,omitempty"`
+
+	Border             string `yaml:"border"`
+	FocusBorder        string `yaml:"focus_border"`
+	SelectedBackground string `yaml:"selected_background"`
+	SelectedForeground string `yaml:"selected_foreground"`
+	// Key is the color of the keys of tables, e.g. header names
+	Key string `yaml:"key"`
+	// Hint is the color of dimmed text, e.g. line numbers
+	Hint string `yaml:"hint"`
+	// Footer is the color of the key binding help
+	Footer string `yaml:"footer"`
+	// MatchBackground and MatchForeground are the colors of search matches
+	MatchBackground string `yaml:"match_background"`
+	MatchForeground string `yaml:"match_foreground"`
+
+	Methods map[string]string `yaml:"methods"`
+	// OtherMethod is the color of the methods not in Methods, e.g. PROPFIND
+	OtherMethod string      `yaml:"other_method"`
+	Status      StatusTheme `yaml:"status"`
+	Json        JsonTheme   `yaml:"json"`
+}
+
+type StatusTheme struct {
+	Informational string `yaml:"1xx"`
+	Success       string `yaml:"2xx"`
+	Redirection   string `yaml:"3xx"`
+	ClientError   string `yaml:"4xx"`
+	ServerError   string `yaml:"5xx"`
+	Unknown       string `yaml:"unknown"`
+	// Error is the color of a request that failed without a response
+	Error string `yaml:"error"`
+}
+
+// JsonTheme colors JSON, and the keys and values of XML, HTML and YAML
+type JsonTheme struct {
+	Key    string `yaml:"key"`
+	String string `yaml:"string"`
+	Number string `yaml:"number"`
+	Bool   string `yaml:"bool"`
+	Null   string `yaml:"null"`
+	Escape string `yaml:"escape"`
+}
+
+var DarkTheme = Theme{
+	Name:               "dark",
+	Border:             "#DCDFE4",
+	FocusBorder:        "#98C379",
+	SelectedBackground: "#61AFEF",
+	SelectedForeground: "#FFFFFF",
+	Key:                "#FFA23D",
+	Hint:               "240",
+	Footer:             "#61AFEF",
+	MatchBackground:    "#E5C07B",
+	MatchForeground:    "#282C34",
+	Methods: map[string]string{
+		"GET":     "#68D696",
+		"POST":    "#EED577",
+		"PUT":     "#74AEF6",
+		"PATCH":   "#C0A8E1",
+		"DELETE":  "#EF968A",
+		"HEAD":    "#68D696",
+		"OPTIONS": "#E55AA8",
+	},
+	OtherMethod: "#56B6C2",
+	Status: StatusTheme{
+		Informational: "#C0A8E1",
+		Success:       "#68D696",
+		Redirection:   "#EED577",
+		ClientError:   "#EED577",
+		ServerError:   "#EF968A",
+		Unknown:       "#EED577",
+		Error:         "#EF968A",
+	},
+	Json: JsonTheme{
+		Key:    "12",
+		String: "2",
+		Number: "3",
+		Bool:   "6",
+		Null:   "8",
+		Escape: "5",
+	},
+}
+
+var LightTheme = Theme{
+	Name:               "light",
+	Border:             "#A0A1A7",
+	FocusBorder:        "#50A14F",
+	SelectedBackground: "#4078F2",
+	SelectedForeground: "#FFFFFF",
+	Key:                "#C18401",
+	Hint:               "#A0A1A7",
+	Footer:             "#4078F2",
+	MatchBackground:    "#FFE58F",
+	MatchForeground:    "#383A42",
+	Methods: map[string]string{
+		"GET":     "#50A14F",
+		"POST":    "#C18401",
+		"PUT":     "#4078F2",
+		"PATCH":   "#A626A4",
+		"DELETE":  "#E45649",
+		"HEAD":    "#50A14F",
+		"OPTIONS": "#CA1243",
+	},
+	OtherMethod: "#0184BC",
+	Status: StatusTheme{
+		Informational: "#A626A4",
+		Success:       "#50A14F",
+		Redirection:   "#C18401",
+		ClientError:   "#C18401",
+		ServerError:   "#E45649",
+		Unknown:       "#C18401",
+		Error:         "#E45649",
+	},
+	Json: JsonTheme{
+		Key:    "#4078F2",
+		String: "#50A14F",
+		Number: "#986801",
+		Bool:   "#0184BC",
+		Null:   "#A0A1A7",
+		Escape: "#A626A4",
+	},
+}
+
+var HighContrastTheme = Theme{
+	Name:               "high-contrast",
+	Border:             "#FFFFFF",
+	FocusBorder:        "#FFFF00",
+	SelectedBackground: "#FFFF00",
+	SelectedForeground: "#000000",
+	Key:                "#00FFFF",
+	Hint:               "#C0C0C0",
+	Footer:             "#FFFFFF",
+	MatchBackground:    "#FF00FF",
+	MatchForeground:    "#000000",
+	Methods: map[string]string{
+		"GET":     "#00FF00",
+		"POST":    "#FFFF00",
+		"PUT":     "#00FFFF",
+		"PATCH":   "#FF00FF",
+		"DELETE":  "#FF5555",
+		"HEAD":    "#00FF00",
+		"OPTIONS": "#FF87FF",
+	},
+	OtherMethod: "#FFAF00",
+	Status: StatusTheme{
+		Informational: "#FF00FF",
+		Success:       "#00FF00",
+		Redirection:   "#FFFF00",
+		ClientError:   "#FFFF00",
+		ServerError:   "#FF5555",
+		Unknown:       "#FFFF00",
+		Error:         "#FF5555",
+	},
+	Json: JsonTheme{
+		Key:    "#00FFFF",
+		String: "#00FF00",
+		Number: "#FFFF00",
+		Bool:   "#FF00FF",
+		Null:   "#C0C0C0",
+		Escape: "#FF87FF",
+	},
+}
+
+var builtinThemes = map[string]Theme{
+	DarkTheme.Name:         DarkTheme,
+	LightTheme.Name:        LightTheme,
+	HighContrastTheme.Name: HighContrastTheme,
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var currentTheme Theme
+
+func init() {
+	SetTheme(DarkTheme)
+}
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() Theme {
+	return currentTheme
+}
+
+// SetTheme sets the colors of the application.
+// It must be called before the models are created.
+func SetTheme(t Theme) {
+	currentTheme = t
+
+	DefaultBorderColor = t.Border
+	FocusBorderColor = t.FocusBorder
+	SelectedBackgroundColor = t.SelectedBackground
+	SelectedForegroundColor = t.SelectedForeground
+	KeyColor = t.Key
+	HintColor = t.Hint
+	FooterColor = t.Footer
+	MatchBackgroundColor = t.MatchBackground
+	MatchForegroundColor = t.MatchForeground
+
+	StatusCode100Color = t.Status.Informational
+	StatusCode200Color = t.Status.Success
+	StatusCode300Color = t.Status.Redirection
+	StatusCode400Color = t.Status.ClientError
+	StatusCode500Color = t.Status.ServerError
+	StatusCodeUnknownColor = t.Status.Unknown
+	StatusErrorColor = t.Status.Error
+
+	setMethodColors(t.Methods, t.OtherMethod)
+	setJsonStyle(t.Json)
+}
+
+// AutoTheme returns the dark or light theme depending on the terminal background
+func AutoTheme() Theme {
+	if lipgloss.HasDarkBackground() {
+		return DarkTheme
+	}
+	return LightTheme
+}
+
+// ThemesDir returns the directory of the user theme files
+func ThemesDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "agora", "themes"), nil
+}
+
+// LoadTheme returns the theme of the given name, which is either
+// "auto", a built-in theme, the name of a file in the themes directory
+// without its extension, or the path of a theme file.
+func LoadTheme(name string) (Theme, error) {
+	if name == "" || name == "auto" {
+		return AutoTheme(), nil
+	}
+	if t, ok := builtinThemes[name]; ok {
+		return t, nil
+	}
+	path := name
+	if !strings.ContainsRune(name, filepath.Separator) && filepath.Ext(name) == "" {
+		dir, err := ThemesDir()
+		if err != nil {
+			return Theme{}, err
+		}
+		path = filepath.Join(dir, name+".yaml")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return Theme{}, fmt.Errorf("unknown theme %q, expected auto, %s or a file in %s",
+				name, strings.Join(ThemeNames(), ", "), dir)
+		}
+	}
+	return LoadThemeFile(path)
+}
+
+// LoadThemeFile reads a theme file. Colors not given in the file
+// are taken from its base theme, which defaults to dark, e.g.
+//
+//	base: light
+//	focus_border: "#D19A66"
+//	methods:
+//	  PATCH: "#C678DD"
+//	json:
+//	  key: "#E06C75"
+func LoadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	var header struct {
+		Base string `yaml:"base"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return Theme{}, fmt.Errorf("%s: %v", path, err)
+	}
+	if header.Base == "" {
+		header.Base = DarkTheme.Name
+	}
+	base, ok := builtinThemes[header.Base]
+	if !ok {
+		return Theme{}, fmt.Errorf("%s: unknown base theme %q, expected one of %s",
+			path, header.Base, strings.Join(ThemeNames(), ", "))
+	}
+
+	t := base
+	t.Methods = nil
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return Theme{}, fmt.Errorf("%s: %v", path, err)
+	}
+	methods := maps.Clone(base.Methods)
+	for method, color := range t.Methods {
+		methods[strings.ToUpper(method)] = color
+	}
+	t.Methods = methods
+	t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return t, nil
+}
@@ -0,0 +1,256 @@
+package styles
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// xmlPrefixes maps the namespaces declared in a document back to their prefixes,
+// since the decoder replaces prefixes with namespaces
+type xmlPrefixes map[string]string
+
+func (p xmlPrefixes) name(n xml.Name) string {
+	switch {
+	case n.Space == "":
+		return n.Local
+	case n.Space == "xmlns":
+		return "xmlns:" + n.Local
+	}
+	prefix, ok := p[n.Space]
+	if !ok {
+		// an undeclared prefix is kept by the decoder
+		prefix = n.Space
+	}
+	if prefix == "" {
+		return n.Local
+	}
+	return prefix + ":" + n.Local
+}
+
+// htmlVoidElements have no end tag in HTML
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// htmlRawTextPattern finds scripts and styles, whose text is not markup
+var (
+	htmlRawTextPattern = regexp.MustCompile(`(?i)<(script|style)\b[^>]*>`)
+	htmlRawTextEnd     = map[string]*regexp.Regexp{
+		"script": regexp.MustCompile(`(?i)</script\s*>`),
+		"style":  regexp.MustCompile(`(?i)</style\s*>`),
+	}
+)
+
+// wrapHtmlRawText wraps scripts and styles in CDATA sections,
+// so that the decoder does not parse them as markup
+func wrapHtmlRawText(s string) string {
+	var b strings.Builder
+	for {
+		loc := htmlRawTextPattern.FindStringSubmatchIndex(s)
+		if loc == nil {
+			break
+		}
+		name := strings.ToLower(s[loc[2]:loc[3]])
+		end := htmlRawTextEnd[name].FindStringIndex(s[loc[1]:])
+		if end == nil {
+			break
+		}
+		content := s[loc[1] : loc[1]+end[0]]
+		b.WriteString(s[:loc[1]])
+		if strings.TrimSpace(content) != "" && !strings.Contains(content, "]]>") {
+			content = "<![CDATA[" + content + "]]>"
+		}
+		b.WriteString(content)
+		s = s[loc[1]+end[0]:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+var xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// xmlFormatter indents and colorizes the tokens of a document.
+// Keys of the JSON theme color tag names, numbers attribute names,
+// strings attribute values, and nulls comments and declarations.
+type xmlFormatter struct {
+	b        strings.Builder
+	html     bool
+	rawText  bool // whether the text is a script or a style
+	depth    int
+	prefixes xmlPrefixes
+}
+
+func (f *xmlFormatter) line(s string) {
+	f.b.WriteString(strings.Repeat("  ", f.depth))
+	f.b.WriteString(s)
+	f.b.WriteString("\n")
+}
+
+// text writes text trimmed at an indentation, keeping the relative
+// indentation of scripts and styles
+func (f *xmlFormatter) text(s string) {
+	lines := strings.Split(strings.Trim(s, "\r\n"), "\n")
+	if !f.rawText {
+		for _, line := range lines {
+			if line = strings.TrimSpace(line); line != "" {
+				f.line(xmlTextEscaper.Replace(line))
+			}
+		}
+		return
+	}
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			n := len(line) - len(strings.TrimLeft(line, " \t"))
+			if indent < 0 || n < indent {
+				indent = n
+			}
+		}
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			f.line(strings.TrimRight(line[indent:], " \t\r"))
+		}
+	}
+}
+
+func isHtmlRawText(name xml.Name) bool {
+	_, ok := htmlRawTextEnd[strings.ToLower(name.Local)]
+	return ok
+}
+
+func paint(pair [2]string, s string) string {
+	return pair[0] + s + pair[1]
+}
+
+func (f *xmlFormatter) startTag(t xml.StartElement, end string) string {
+	for _, attr := range t.Attr {
+		if attr.Name.Space == "xmlns" {
+			f.prefixes[attr.Value] = attr.Name.Local
+		} else if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			f.prefixes[attr.Value] = ""
+		}
+	}
+	var b strings.Builder
+	b.WriteString(paint(jsonStyle.Key, "<"+f.prefixes.name(t.Name)))
+	for _, attr := range t.Attr {
+		b.WriteString(" ")
+		b.WriteString(paint(jsonStyle.Number, f.prefixes.name(attr.Name)))
+		b.WriteString("=")
+		value := strings.ReplaceAll(xmlTextEscaper.Replace(attr.Value), `"`, "&quot;")
+		b.WriteString(paint(jsonStyle.String, `"`+value+`"`))
+	}
+	b.WriteString(paint(jsonStyle.Key, end))
+	return b.String()
+}
+
+func (f *xmlFormatter) endTag(t xml.EndElement) string {
+	return paint(jsonStyle.Key, "</"+f.prefixes.name(t.Name)+">")
+}
+
+// emptyElement formats an element without content
+func (f *xmlFormatter) emptyElement(start xml.StartElement, end xml.EndElement) string {
+	switch {
+	case !f.html:
+		return f.startTag(start, "/>")
+	case htmlVoidElements[strings.ToLower(start.Name.Local)]:
+		return f.startTag(start, ">")
+	default:
+		return f.startTag(start, ">") + f.endTag(end)
+	}
+}
+
+func (f *xmlFormatter) format(tokens []xml.Token) string {
+	for i := 0; i < len(tokens); i++ {
+		switch t := tokens[i].(type) {
+		case xml.StartElement:
+			if i+1 < len(tokens) {
+				if end, ok := tokens[i+1].(xml.EndElement); ok {
+					f.line(f.emptyElement(t, end))
+					i++
+					continue
+				}
+			}
+			// keep a single line of text on the line of its element
+			if i+2 < len(tokens) {
+				text, ok := tokens[i+1].(xml.CharData)
+				end, isEnd := tokens[i+2].(xml.EndElement)
+				if trimmed := strings.TrimSpace(string(text)); ok && isEnd && !strings.Contains(trimmed, "\n") {
+					if !f.html || !isHtmlRawText(t.Name) {
+						trimmed = xmlTextEscaper.Replace(trimmed)
+					}
+					f.line(f.startTag(t, ">") + trimmed + f.endTag(end))
+					i += 2
+					continue
+				}
+			}
+			f.line(f.startTag(t, ">"))
+			f.rawText = f.html && isHtmlRawText(t.Name)
+			f.depth++
+		case xml.EndElement:
+			f.rawText = false
+			f.depth = max(0, f.depth-1)
+			f.line(f.endTag(t))
+		case xml.CharData:
+			f.text(string(t))
+		case xml.Comment:
+			f.line(paint(jsonStyle.Null, "<!--"+string(t)+"-->"))
+		case xml.ProcInst:
+			f.line(paint(jsonStyle.Null, "<?"+t.Target+" "+string(t.Inst)+"?>"))
+		case xml.Directive:
+			f.line(paint(jsonStyle.Null, "<!"+string(t)+">"))
+		}
+	}
+	return strings.TrimSuffix(f.b.String(), "\n")
+}
+
+func formatMarkup(s string, html bool) (string, error) {
+	if html {
+		s = wrapHtmlRawText(s)
+	}
+	decoder := xml.NewDecoder(strings.NewReader(s))
+	if html {
+		decoder.Strict = false
+		decoder.AutoClose = xml.HTMLAutoClose
+		decoder.Entity = xml.HTMLEntity
+	}
+	// the charset is decoded with the response
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	var tokens []xml.Token
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		tokens = append(tokens, xml.CopyToken(token))
+	}
+	if len(tokens) == 0 {
+		return "", errors.New("empty document")
+	}
+	f := xmlFormatter{
+		html:     html,
+		prefixes: xmlPrefixes{"http://www.w3.org/XML/1998/namespace": "xml"},
+	}
+	return f.format(tokens), nil
+}
+
+// FormatXml indents and colorizes an XML document
+func FormatXml(s string) (string, error) {
+	return formatMarkup(s, false)
+}
+
+// FormatHtml indents and colorizes an HTML document. Unclosed elements
+// are closed, so the result may differ from the source.
+func FormatHtml(s string) (string, error) {
+	return formatMarkup(s, true)
+}
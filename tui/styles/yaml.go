@@ -0,0 +1,118 @@
+package styles
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrettifyYaml re-indents each document of a YAML stream, keeping comments
+func PrettifyYaml(s string) (string, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(s))
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	count := 0
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := encoder.Encode(&node); err != nil {
+			return "", err
+		}
+		count++
+	}
+	if count == 0 {
+		return "", errors.New("empty document")
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+var (
+	// a key at the start of a line, after indentation and sequence dashes
+	yamlKeyPattern    = regexp.MustCompile(`^(\s*(?:- +)*)("[^"]*"|'[^']*'|[^\s#'"-][^#]*?|-[^\s#][^#]*?):(\s+|$)(.*)$`)
+	yamlItemPattern   = regexp.MustCompile(`^(\s*(?:- +)+)(.*)$`)
+	yamlNumberPattern = regexp.MustCompile(`^[-+]?(\.inf|\.Inf|\.INF|\.nan|\.NaN|\.NAN|0x[0-9a-fA-F]+|0o[0-7]+|[0-9][0-9_]*(\.[0-9]*)?([eE][-+]?[0-9]+)?|\.[0-9]+([eE][-+]?[0-9]+)?)$`)
+	yamlBlockPattern  = regexp.MustCompile(`^[|>][-+0-9]*$`)
+)
+
+// colorizeYamlScalar colorizes a value and its trailing comment
+func colorizeYamlScalar(value string) string {
+	var comment string
+	if i := strings.Index(value, " #"); i >= 0 && !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+		value, comment = value[:i], value[i:]
+	} else if strings.HasPrefix(value, "#") {
+		return paint(jsonStyle.Null, value)
+	}
+	var colored string
+	switch trimmed := strings.TrimSpace(value); {
+	case trimmed == "" || yamlBlockPattern.MatchString(trimmed) ||
+		strings.ContainsAny(trimmed[:1], "&*![{"):
+		colored = value
+	case trimmed == "null" || trimmed == "Null" || trimmed == "NULL" || trimmed == "~":
+		colored = paint(jsonStyle.Null, value)
+	case trimmed == "true" || trimmed == "True" || trimmed == "TRUE" ||
+		trimmed == "false" || trimmed == "False" || trimmed == "FALSE":
+		colored = paint(jsonStyle.True, value)
+	case yamlNumberPattern.MatchString(trimmed):
+		colored = paint(jsonStyle.Number, value)
+	default:
+		colored = paint(jsonStyle.String, value)
+	}
+	if comment != "" {
+		colored += paint(jsonStyle.Null, comment)
+	}
+	return colored
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// ColorizeYaml colorizes YAML line by line, with the colors of the JSON theme
+func ColorizeYaml(s string) string {
+	lines := strings.Split(s, "\n")
+	block := -1 // indentation of the key of a block scalar being colored
+	for i, line := range lines {
+		if block >= 0 {
+			if strings.TrimSpace(line) == "" || indentation(line) > block {
+				lines[i] = paint(jsonStyle.String, line)
+				continue
+			}
+			block = -1
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || trimmed == "---" || trimmed == "...":
+		case strings.HasPrefix(trimmed, "#"):
+			lines[i] = paint(jsonStyle.Null, line)
+		default:
+			if m := yamlKeyPattern.FindStringSubmatch(line); m != nil {
+				lines[i] = m[1] + paint(jsonStyle.Key, m[2]) + ":" + m[3] + colorizeYamlScalar(m[4])
+				if yamlBlockPattern.MatchString(strings.TrimSpace(m[4])) {
+					block = indentation(line)
+				}
+			} else if m := yamlItemPattern.FindStringSubmatch(line); m != nil {
+				lines[i] = m[1] + colorizeYamlScalar(m[2])
+				if yamlBlockPattern.MatchString(strings.TrimSpace(m[2])) {
+					block = indentation(line)
+				}
+			} else {
+				lines[i] = colorizeYamlScalar(line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
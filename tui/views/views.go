@@ -13,6 +13,10 @@ const (
 	SelectMethodDialogView
 	TextInputDialogView
 	TextAreaDialogView
+	SelectDialogView
+	ConfirmDialogView
+	FinderDialogView
+	HelpDialogView
 )
 
 func IsPaneView(v View) bool {